@@ -0,0 +1,96 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package log
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const benchmarkModule = "benchmark-module"
+
+func newBenchmarkLogger(opts ...Option) *Log {
+	SetLevel(benchmarkModule, INFO)
+
+	return New(benchmarkModule, append([]Option{WithRoute(zapcore.AddSync(io.Discard), DEBUG, FATAL)}, opts...)...)
+}
+
+func BenchmarkNew(b *testing.B) {
+	b.ReportAllocs()
+
+	for b.Loop() {
+		newBenchmarkLogger()
+	}
+}
+
+// BenchmarkWith creates a child logger per request, as is typical in request handlers, which
+// is then used by both the context and the non-context logging functions.
+func BenchmarkWith(b *testing.B) {
+	logger := newBenchmarkLogger()
+	ctx := context.Background()
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		child := logger.With(zap.String("request_id", "abc123"), zap.Int("attempt", 1))
+
+		child.Info("Request received")
+		child.Infoc(ctx, "Request processed")
+	}
+}
+
+// BenchmarkNamed creates a named child of a per-request logger.
+func BenchmarkNamed(b *testing.B) {
+	logger := newBenchmarkLogger()
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		logger.With(zap.String("request_id", "abc123")).Named("issuer").Info("Request received")
+	}
+}
+
+func BenchmarkInfo(b *testing.B) {
+	logger := newBenchmarkLogger().With(zap.String("request_id", "abc123"))
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		logger.Info("Request received", zap.Int("attempt", 1))
+	}
+}
+
+func BenchmarkInfoc(b *testing.B) {
+	logger := newBenchmarkLogger(WithSpanEvents()).With(zap.String("request_id", "abc123"))
+
+	ctx, span := trace.NewTracerProvider().Tracer("benchmark").Start(context.Background(), "span")
+	defer span.End()
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		logger.Infoc(ctx, "Request received", zap.Int("attempt", 1))
+	}
+}
+
+func BenchmarkDebugcDisabled(b *testing.B) {
+	logger := newBenchmarkLogger().With(zap.String("request_id", "abc123"))
+
+	ctx, span := trace.NewTracerProvider().Tracer("benchmark").Start(context.Background(), "span")
+	defer span.End()
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		logger.Debugc(ctx, "Verifying credential", zap.String("id", "abc"), zap.Int("index", 1))
+	}
+}
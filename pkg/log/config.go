@@ -0,0 +1,460 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+// Config describes the complete logging configuration. It may be loaded from YAML or JSON
+// (see ParseConfig and LoadConfig) and is applied using ApplyConfig.
+type Config struct {
+	// Encoding is the default encoding of all sinks (json, console, logfmt, pretty, gcp, ecs or datadog).
+	Encoding Encoding `json:"encoding,omitempty" yaml:"encoding,omitempty"`
+	// Keys overrides the keys of the standard log fields (see WithEncoderKeys).
+	Keys *EncoderKeys `json:"keys,omitempty" yaml:"keys,omitempty"`
+	// TimeFormat is the format of the timestamp (see WithTimeFormat).
+	TimeFormat string `json:"timeFormat,omitempty" yaml:"timeFormat,omitempty"`
+	// Level is the default log level.
+	Level string `json:"level,omitempty" yaml:"level,omitempty"`
+	// Modules contains the log level of individual modules.
+	Modules map[string]string `json:"modules,omitempty" yaml:"modules,omitempty"`
+	// Sinks contains the outputs of the log entries. If no sinks are specified then ERROR
+	// (and above) logs are written to stderr and all other logs are written to stdout.
+	Sinks []SinkConfig `json:"sinks,omitempty" yaml:"sinks,omitempty"`
+	// Sampling limits the number of log entries with the same level and message that are written.
+	Sampling *SamplingConfig `json:"sampling,omitempty" yaml:"sampling,omitempty"`
+	// Redaction contains the rules for redacting the values of log fields.
+	Redaction []RedactionConfig `json:"redaction,omitempty" yaml:"redaction,omitempty"`
+	// Fields contains the static fields that are output with every log.
+	Fields map[string]interface{} `json:"fields,omitempty" yaml:"fields,omitempty"`
+}
+
+// SinkType defines the type of sink.
+type SinkType = string
+
+// Sink types.
+const (
+	SinkStdOut  SinkType = "stdout"
+	SinkStdErr  SinkType = "stderr"
+	SinkFile    SinkType = "file"
+	SinkNetwork SinkType = "network"
+)
+
+// SinkConfig describes an output for log entries.
+type SinkConfig struct {
+	// Type is the type of sink (stdout, stderr, file or network).
+	Type SinkType `json:"type" yaml:"type"`
+	// Path is the path of the file for the file sink.
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+	// Network is the network (tcp or udp) for the network sink. Defaults to tcp.
+	Network string `json:"network,omitempty" yaml:"network,omitempty"`
+	// Address is the address (host:port) for the network sink. An entry is dropped (and the connection is
+	// closed) if it can't be written within 5 seconds. After a failure, entries are dropped without connecting
+	// until a retry interval (from 1 up to 30 seconds) has passed, and the connection is then re-established
+	// on the next write.
+	Address string `json:"address,omitempty" yaml:"address,omitempty"`
+	// Encoding is the encoding of the sink. Defaults to the encoding in Config.
+	Encoding Encoding `json:"encoding,omitempty" yaml:"encoding,omitempty"`
+	// MinLevel is the minimum level of the entries that are written to the sink. Defaults to trace.
+	MinLevel string `json:"minLevel,omitempty" yaml:"minLevel,omitempty"`
+	// MaxLevel is the maximum level of the entries that are written to the sink. Defaults to fatal.
+	MaxLevel string `json:"maxLevel,omitempty" yaml:"maxLevel,omitempty"`
+	// Modules restricts the sink to the given modules (and their child modules). Defaults to all modules.
+	Modules []string `json:"modules,omitempty" yaml:"modules,omitempty"`
+}
+
+// SamplingConfig describes the sampling of log entries. For each interval (tick), the first
+// N entries with the same level and message are written and thereafter only every Mth entry is written.
+// At least one of First and Thereafter must be positive.
+type SamplingConfig struct {
+	// Tick is the sampling interval, for example "1s".
+	Tick string `json:"tick" yaml:"tick"`
+	// First is the number of entries that are written in each interval.
+	First int `json:"first" yaml:"first"`
+	// Thereafter specifies that every Mth entry is written after the first N entries in each interval.
+	Thereafter int `json:"thereafter" yaml:"thereafter"`
+}
+
+// RedactionConfig describes a rule for redacting the values of log fields. If only a field is
+// specified then the entire value of the field is replaced. If a pattern is specified then the matching
+// parts of string values are replaced (in the given field or, if no field is specified, in all string fields).
+type RedactionConfig struct {
+	// Field is the key of the field to redact.
+	Field string `json:"field,omitempty" yaml:"field,omitempty"`
+	// Pattern is a regular expression that matches the values to redact.
+	Pattern string `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+	// Replacement is the replacement text. Defaults to "[REDACTED]".
+	Replacement string `json:"replacement,omitempty" yaml:"replacement,omitempty"`
+}
+
+// ParseConfig parses the given YAML or JSON logging configuration. Unknown keys result in an error.
+func ParseConfig(data []byte) (*Config, error) {
+	cfg := &Config{}
+
+	trimmed := bytes.TrimSpace(data)
+
+	if len(trimmed) > 0 && trimmed[0] == '{' {
+		decoder := json.NewDecoder(bytes.NewReader(trimmed))
+		decoder.DisallowUnknownFields()
+
+		if err := decoder.Decode(cfg); err != nil {
+			return nil, fmt.Errorf("parse JSON logging config: %w", err)
+		}
+
+		return cfg, nil
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(trimmed))
+	decoder.KnownFields(true)
+
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parse YAML logging config: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// LoadConfig loads the YAML or JSON logging configuration from the given file.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("read logging config: %w", err)
+	}
+
+	return ParseConfig(data)
+}
+
+// ApplyConfig validates the given configuration and, if valid, applies it as a whole. The log levels
+// are applied immediately. The remaining settings replace the defaults of all loggers, including the loggers
+// that were already created (see Configure), unless the logger overrides the setting explicitly. The file and
+// network sinks of a previously applied configuration are closed once they are replaced.
+//
+// The defaults which can't be expressed in a Config (for example, the hooks and stack traces set with
+// Configure) are kept, so they aren't removed when the configuration is reloaded.
+//
+// If the configuration is invalid then an error that describes each problem is returned and nothing is changed.
+func ApplyConfig(cfg *Config) error {
+	o, opened, err := cfg.options()
+	if err != nil {
+		return err
+	}
+
+	defaultLevel, moduleLevelPairs, err := cfg.levels()
+	if err != nil {
+		closeOutputs(opened)
+
+		return err
+	}
+
+	// The sinks of the previous configuration are closed once they are replaced.
+	defaults.setOutputs(func(d *options) {
+		d.encoding = o.encoding
+		d.encoderKeys = o.encoderKeys
+		d.timeEncoder = o.timeEncoder
+		d.stdOut, d.stdErr = o.stdOut, o.stdErr
+		d.routes, d.routesSet = o.routes, false
+		d.sampling = o.sampling
+		d.redactionRules = o.redactionRules
+		d.staticFields = o.staticFields
+	}, opened)
+
+	// The levels are set after the defaults are unlocked since the level change listeners may create loggers.
+	levels.setSpec(defaultLevel, moduleLevelPairs)
+
+	return nil
+}
+
+func (c *Config) levels() (Level, []moduleLevelPair, error) {
+	defaultLogLevel := minLogLevel - 1
+
+	var errs []error
+
+	if c.Level != "" {
+		level, err := ParseLevel(c.Level)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("level %q: %w", c.Level, err))
+		}
+
+		defaultLogLevel = level
+	}
+
+	moduleLevelPairs := make([]moduleLevelPair, 0, len(c.Modules))
+
+	for module, levelStr := range c.Modules {
+		level, err := ParseLevel(levelStr)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("module %q level %q: %w", module, levelStr, err))
+
+			continue
+		}
+
+		moduleLevelPairs = append(moduleLevelPairs, moduleLevelPair{module: module, logLevel: level})
+	}
+
+	if len(errs) > 0 {
+		return defaultLogLevel, nil, errors.Join(errs...)
+	}
+
+	return defaultLogLevel, moduleLevelPairs, nil
+}
+
+// options returns the options of the configuration and the sinks which were opened for them.
+//
+//nolint:cyclop
+func (c *Config) options() (*options, []*openedOutput, error) {
+	o := newDefaultOptions().options
+
+	var errs []error
+
+	if c.Encoding != "" {
+		if isSupportedEncoding(c.Encoding) {
+			o.encoding = strings.ToLower(c.Encoding)
+		} else {
+			errs = append(errs, fmt.Errorf("unsupported encoding %q", c.Encoding))
+		}
+	}
+
+	o.encoderKeys = c.Keys
+
+	if c.TimeFormat != "" {
+		timeEncoder, err := parseTimeFormat(c.TimeFormat)
+		if err != nil {
+			errs = append(errs, err)
+		} else {
+			o.timeEncoder = timeEncoder
+		}
+	}
+
+	if c.Sampling != nil {
+		sampling, err := c.Sampling.options()
+		if err != nil {
+			errs = append(errs, err)
+		} else {
+			o.sampling = sampling
+		}
+	}
+
+	for i, r := range c.Redaction {
+		rule, err := r.rule()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("redaction rule %d: %w", i, err))
+		} else {
+			o.redactionRules = append(o.redactionRules, rule)
+		}
+	}
+
+	o.staticFields = staticFields(c.Fields)
+
+	for i := range c.Sinks {
+		if err := c.Sinks[i].validate(); err != nil {
+			errs = append(errs, fmt.Errorf("sink %d: %w", i, err))
+		}
+	}
+
+	if _, _, err := c.levels(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if len(errs) > 0 {
+		return nil, nil, errors.Join(errs...)
+	}
+
+	// Sinks are opened last so that no files or connections are created if the configuration is invalid.
+	opened := make([]*openedOutput, 0, len(c.Sinks))
+
+	for i := range c.Sinks {
+		s, closeSink, err := c.Sinks[i].open()
+		if err != nil {
+			// Close the sinks which were already opened.
+			closeOutputs(opened)
+
+			return nil, nil, fmt.Errorf("sink %d: %w", i, err)
+		}
+
+		opened = append(opened, &openedOutput{output: s.output, close: closeSink})
+
+		o.routes = append(o.routes, s)
+	}
+
+	return &o, opened, nil
+}
+
+func (c *SamplingConfig) options() (*samplingOptions, error) {
+	tick, err := time.ParseDuration(c.Tick)
+	if err != nil {
+		return nil, fmt.Errorf("sampling tick %q: %w", c.Tick, err)
+	}
+
+	if tick <= 0 || c.First < 0 || c.Thereafter < 0 {
+		return nil, errors.New("sampling tick must be positive and first and thereafter must not be negative")
+	}
+
+	// The sampler would drop all entries.
+	if c.First == 0 && c.Thereafter == 0 {
+		return nil, errors.New("sampling first or thereafter must be positive")
+	}
+
+	return &samplingOptions{tick: tick, first: c.First, thereafter: c.Thereafter}, nil
+}
+
+func (c *RedactionConfig) rule() (*redactionRule, error) {
+	if c.Field == "" && c.Pattern == "" {
+		return nil, errors.New("field or pattern is required")
+	}
+
+	rule := &redactionRule{
+		field:       c.Field,
+		replacement: c.Replacement,
+	}
+
+	if rule.replacement == "" {
+		rule.replacement = defaultRedactionReplacement
+	}
+
+	if c.Pattern != "" {
+		pattern, err := regexp.Compile(c.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("pattern %q: %w", c.Pattern, err)
+		}
+
+		rule.pattern = pattern
+	}
+
+	return rule, nil
+}
+
+func (c *SinkConfig) validate() error {
+	var errs []error
+
+	switch c.Type {
+	case SinkStdOut, SinkStdErr:
+	case SinkFile:
+		if c.Path == "" {
+			errs = append(errs, errors.New("path is required for file sink"))
+		}
+	case SinkNetwork:
+		if c.Address == "" {
+			errs = append(errs, errors.New("address is required for network sink"))
+		}
+
+		switch c.Network {
+		case "", "tcp", "udp":
+		default:
+			errs = append(errs, fmt.Errorf("unsupported network %q", c.Network))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("unsupported sink type %q", c.Type))
+	}
+
+	if c.Encoding != "" && !isSupportedEncoding(c.Encoding) {
+		errs = append(errs, fmt.Errorf("unsupported encoding %q", c.Encoding))
+	}
+
+	if _, _, err := c.levelRange(); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c *SinkConfig) levelRange() (Level, Level, error) {
+	minLevel, maxLevel := minLogLevel, maxLogLevel
+
+	var err error
+
+	if c.MinLevel != "" {
+		minLevel, err = ParseLevel(c.MinLevel)
+		if err != nil {
+			return minLevel, maxLevel, fmt.Errorf("min level %q: %w", c.MinLevel, err)
+		}
+	}
+
+	if c.MaxLevel != "" {
+		maxLevel, err = ParseLevel(c.MaxLevel)
+		if err != nil {
+			return minLevel, maxLevel, fmt.Errorf("max level %q: %w", c.MaxLevel, err)
+		}
+	}
+
+	if minLevel > maxLevel {
+		return minLevel, maxLevel, fmt.Errorf("min level %s is greater than max level %s", minLevel, maxLevel)
+	}
+
+	return minLevel, maxLevel, nil
+}
+
+// open opens the sink and returns its route and a function which closes it.
+func (c *SinkConfig) open() (*route, func(), error) {
+	minLevel, maxLevel, err := c.levelRange()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r := &route{
+		encoding: strings.ToLower(c.Encoding),
+		minLevel: minLevel,
+		maxLevel: maxLevel,
+		modules:  c.Modules,
+	}
+
+	closeSink := func() {}
+
+	switch c.Type {
+	case SinkStdOut:
+		r.output = os.Stdout
+	case SinkStdErr:
+		r.output = os.Stderr
+	case SinkFile:
+		r.output, closeSink, err = openOutput(c.Path)
+	case SinkNetwork:
+		var w *networkWriter
+
+		w, err = newNetworkWriter(c.Network, c.Address)
+		if err == nil {
+			r.output = w
+			closeSink = func() { _ = w.Close() } //nolint:errcheck
+		}
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return r, closeSink, nil
+}
+
+// staticFields converts the given map to fields, sorted by key.
+func staticFields(values map[string]interface{}) []zap.Field {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	fields := make([]zap.Field, 0, len(keys))
+
+	for _, key := range keys {
+		fields = append(fields, zap.Any(key, values[key]))
+	}
+
+	return fields
+}
@@ -0,0 +1,390 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package log
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const yamlConfig = `
+encoding: json
+timeFormat: rfc3339
+level: warn
+modules:
+  config-module1: debug
+  config-module2: error
+sinks:
+  - type: file
+    path: "%s"
+    maxLevel: info
+  - type: file
+    path: "%s"
+    minLevel: warn
+    encoding: console
+sampling:
+  tick: 1s
+  first: 2
+  thereafter: 0
+redaction:
+  - field: token
+  - pattern: "secret-[0-9]+"
+    replacement: "***"
+fields:
+  service: my-service
+  instance: 2
+`
+
+const jsonConfig = `{
+  "encoding": "console",
+  "level": "info",
+  "modules": {"config-module1": "debug"},
+  "sinks": [{"type": "stdout", "maxLevel": "warn"}, {"type": "stderr", "minLevel": "error"}],
+  "fields": {"service": "my-service"}
+}`
+
+func TestParseConfig(t *testing.T) {
+	t.Run("YAML", func(t *testing.T) {
+		cfg, err := ParseConfig([]byte(yamlConfig))
+		require.NoError(t, err)
+
+		require.Equal(t, JSON, cfg.Encoding)
+		require.Equal(t, "rfc3339", cfg.TimeFormat)
+		require.Equal(t, "warn", cfg.Level)
+		require.Equal(t, map[string]string{"config-module1": "debug", "config-module2": "error"}, cfg.Modules)
+		require.Len(t, cfg.Sinks, 2)
+		require.Equal(t, SinkFile, cfg.Sinks[0].Type)
+		require.Equal(t, "info", cfg.Sinks[0].MaxLevel)
+		require.Equal(t, Console, cfg.Sinks[1].Encoding)
+		require.Equal(t, &SamplingConfig{Tick: "1s", First: 2}, cfg.Sampling)
+		require.Len(t, cfg.Redaction, 2)
+		require.Equal(t, "my-service", cfg.Fields["service"])
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		cfg, err := ParseConfig([]byte(jsonConfig))
+		require.NoError(t, err)
+
+		require.Equal(t, Console, cfg.Encoding)
+		require.Equal(t, "info", cfg.Level)
+		require.Len(t, cfg.Sinks, 2)
+		require.Equal(t, SinkStdErr, cfg.Sinks[1].Type)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		cfg, err := ParseConfig(nil)
+		require.NoError(t, err)
+		require.Equal(t, &Config{}, cfg)
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		_, err := ParseConfig([]byte("encodings: json"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "parse YAML logging config")
+
+		_, err = ParseConfig([]byte(`{"encodings": "json"}`))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "parse JSON logging config")
+	})
+
+	t.Run("load file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "log.json")
+		require.NoError(t, os.WriteFile(path, []byte(jsonConfig), 0o600))
+
+		cfg, err := LoadConfig(path)
+		require.NoError(t, err)
+		require.Equal(t, Console, cfg.Encoding)
+
+		_, err = LoadConfig(filepath.Join(t.TempDir(), "missing.json"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "read logging config")
+	})
+}
+
+func TestApplyConfig(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		resetDefaults(t)
+		resetLoggingLevels()
+		t.Cleanup(resetLoggingLevels)
+
+		dir := t.TempDir()
+		outFile := filepath.Join(dir, "out.log")
+		errFile := filepath.Join(dir, "err.log")
+
+		cfg, err := ParseConfig([]byte(fmt.Sprintf(yamlConfig, outFile, errFile)))
+		require.NoError(t, err)
+
+		require.NoError(t, ApplyConfig(cfg))
+
+		require.Equal(t, WARNING, GetLevel(""))
+		require.Equal(t, DEBUG, GetLevel("config-module1"))
+		require.Equal(t, ERROR, GetLevel("config-module2"))
+
+		logger := New("config-module1")
+
+		logger.Debug("Sample debug log", WithToken("my-token"))
+		logger.Info("Sample info log", WithName("secret-1234"))
+		logger.Warn("Sample warn log")
+		logger.Error("Sample error log")
+
+		// Sampling: only the first two entries with the same message are written.
+		for i := 0; i < 5; i++ {
+			logger.Info("Sampled log")
+		}
+
+		out, err := os.ReadFile(outFile) //nolint:gosec
+		require.NoError(t, err)
+
+		require.Contains(t, string(out), `"msg":"Sample debug log"`)
+		require.Contains(t, string(out), `"token":"[REDACTED]"`)
+		require.NotContains(t, string(out), "my-token")
+		require.Contains(t, string(out), `"name":"***"`)
+		require.Contains(t, string(out), `"service":"my-service"`)
+		require.Contains(t, string(out), `"instance":2`)
+		require.NotContains(t, string(out), "Sample warn log")
+		require.Equal(t, 2, countLines(string(out), "Sampled log"))
+
+		errOut, err := os.ReadFile(errFile) //nolint:gosec
+		require.NoError(t, err)
+
+		require.Contains(t, string(errOut), "[config-module1]")
+		require.Contains(t, string(errOut), "Sample warn log")
+		require.Contains(t, string(errOut), "Sample error log")
+		require.NotContains(t, string(errOut), "Sample info log")
+
+		t.Run("explicit options take precedence", func(t *testing.T) {
+			stdOut := newMockWriter()
+
+			logger := New("config-module1", WithStdOut(stdOut), WithEncoding(Console))
+			logger.Info("Sample info log", WithToken("my-token"))
+
+			require.Contains(t, stdOut.String(), "Sample info log")
+			require.Contains(t, stdOut.String(), `"token": "[REDACTED]"`)
+		})
+	})
+
+	t.Run("network sink", func(t *testing.T) {
+		resetDefaults(t)
+
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+
+		defer func() {
+			require.NoError(t, listener.Close())
+		}()
+
+		received := make(chan string, 1)
+
+		go func() {
+			conn, e := listener.Accept()
+			if e != nil {
+				return
+			}
+
+			defer conn.Close() //nolint:errcheck
+
+			line, e := bufio.NewReader(conn).ReadString('\n')
+			if e == nil {
+				received <- line
+			}
+		}()
+
+		require.NoError(t, ApplyConfig(&Config{
+			Sinks: []SinkConfig{{Type: SinkNetwork, Address: listener.Addr().String()}},
+		}))
+
+		New("config-network").Info("Sample network log")
+
+		select {
+		case line := <-received:
+			require.Contains(t, line, "Sample network log")
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "timed out waiting for log")
+		}
+	})
+
+	t.Run("opened sinks are closed on error", func(t *testing.T) {
+		resetDefaults(t)
+
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+
+		defer func() {
+			require.NoError(t, listener.Close())
+		}()
+
+		closed := make(chan error, 1)
+
+		go func() {
+			conn, e := listener.Accept()
+			if e != nil {
+				return
+			}
+
+			defer conn.Close() //nolint:errcheck
+
+			// The read returns (io.EOF) when the connection is closed by the logger.
+			_, e = conn.Read(make([]byte, 1))
+			closed <- e
+		}()
+
+		err = ApplyConfig(&Config{
+			Sinks: []SinkConfig{
+				{Type: SinkNetwork, Address: listener.Addr().String()},
+				{Type: SinkFile, Path: filepath.Join(t.TempDir(), "missing", "out.log")},
+			},
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "sink 1:")
+
+		select {
+		case e := <-closed:
+			require.ErrorIs(t, e, io.EOF)
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "timed out waiting for the connection to be closed")
+		}
+	})
+
+	t.Run("replaced sinks are closed", func(t *testing.T) {
+		resetDefaults(t)
+
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+
+		defer func() {
+			require.NoError(t, listener.Close())
+		}()
+
+		closed := make(chan error, 1)
+
+		go func() {
+			conn, e := listener.Accept()
+			if e != nil {
+				return
+			}
+
+			defer conn.Close() //nolint:errcheck
+
+			// The read returns (io.EOF) when the connection is closed by the logger.
+			_, e = conn.Read(make([]byte, 1))
+			closed <- e
+		}()
+
+		require.NoError(t, ApplyConfig(&Config{
+			Sinks: []SinkConfig{{Type: SinkNetwork, Address: listener.Addr().String()}},
+		}))
+
+		// The configuration is reloaded.
+		require.NoError(t, ApplyConfig(&Config{
+			Sinks: []SinkConfig{{Type: SinkFile, Path: filepath.Join(t.TempDir(), "out.log")}},
+		}))
+
+		select {
+		case e := <-closed:
+			require.ErrorIs(t, e, io.EOF)
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "timed out waiting for the connection to be closed")
+		}
+	})
+
+	t.Run("level change listener creates logger", func(t *testing.T) {
+		resetDefaults(t)
+
+		const module = "config-listener"
+
+		t.Cleanup(func() { SetLevel(module, INFO) })
+
+		stdOut := newMockWriter()
+
+		unsubscribe := OnLevelChange(module, func(string, Level, Level) {
+			New(module, WithStdOut(stdOut)).Debug("Sample debug log")
+		})
+		defer unsubscribe()
+
+		// The listener is invoked after the defaults are unlocked, so it doesn't deadlock.
+		require.NoError(t, ApplyConfig(&Config{Modules: map[string]string{module: "debug"}}))
+
+		require.Contains(t, stdOut.String(), "Sample debug log")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		resetDefaults(t)
+		resetLoggingLevels()
+
+		missingDir := filepath.Join(t.TempDir(), "missing")
+
+		err := ApplyConfig(&Config{
+			Encoding:   "xml",
+			TimeFormat: "invalid",
+			Level:      "verbose",
+			Modules:    map[string]string{"config-module3": "loud"},
+			Sinks: []SinkConfig{
+				{Type: "kafka"},
+				{Type: SinkFile},
+				{Type: SinkNetwork, Network: "unix"},
+				{Type: SinkStdOut, MinLevel: "error", MaxLevel: "info"},
+				{Type: SinkFile, Path: filepath.Join(missingDir, "out.log")},
+			},
+			Sampling:  &SamplingConfig{Tick: "soon"},
+			Redaction: []RedactionConfig{{}, {Pattern: "("}},
+		})
+		require.Error(t, err)
+
+		require.Contains(t, err.Error(), `unsupported encoding "xml"`)
+		require.Contains(t, err.Error(), `invalid time format "invalid"`)
+		require.Contains(t, err.Error(), `level "verbose"`)
+		require.Contains(t, err.Error(), `module "config-module3" level "loud"`)
+		require.Contains(t, err.Error(), `sink 0: unsupported sink type "kafka"`)
+		require.Contains(t, err.Error(), "sink 1: path is required for file sink")
+		require.Contains(t, err.Error(), "sink 2: address is required for network sink")
+		require.Contains(t, err.Error(), `unsupported network "unix"`)
+		require.Contains(t, err.Error(), "sink 3: min level ERROR is greater than max level INFO")
+		require.Contains(t, err.Error(), `sampling tick "soon"`)
+		require.Contains(t, err.Error(), "redaction rule 0: field or pattern is required")
+		require.Contains(t, err.Error(), `redaction rule 1: pattern "("`)
+
+		// Nothing should have been applied.
+		require.Equal(t, INFO, GetLevel("config-module3"))
+		require.Equal(t, DefaultEncoding, getOptions(nil).encoding)
+
+		err = ApplyConfig(&Config{
+			Sinks: []SinkConfig{{Type: SinkFile, Path: filepath.Join(missingDir, "out.log")}},
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "sink 0: open output")
+
+		err = ApplyConfig(&Config{Sampling: &SamplingConfig{Tick: "-1s"}})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "sampling tick must be positive")
+
+		// Sampling with neither first nor thereafter would drop all entries.
+		err = ApplyConfig(&Config{Sampling: &SamplingConfig{Tick: "1s"}})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "sampling first or thereafter must be positive")
+	})
+}
+
+func countLines(s, substr string) int {
+	count := 0
+
+	for _, line := range strings.Split(s, "\n") {
+		if strings.Contains(line, substr) {
+			count++
+		}
+	}
+
+	return count
+}
@@ -0,0 +1,179 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package log
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Configure sets the default options of all loggers, including the loggers that were already created
+// (for example, package-level loggers which are created before the configuration is read). Options
+// that are passed to New take precedence over the defaults.
+//
+// The outputs, routes, encoding, time format, encoder keys, stack traces, sampling, redaction rules,
+// hooks and static fields of existing loggers are replaced at once, so outputs may be switched at runtime
+// without recreating the loggers. The remaining options (for example, WithCallerSkip, WithFields,
+// WithSpanEvents, WithMetrics and WithFlightRecorder) only apply to loggers created afterwards.
+//
+// If an encoding is not supported then an error is returned and nothing is changed.
+//
+// Example:
+//
+//	err := log.Configure(log.WithEncoding(log.JSON), log.WithStdOut(file))
+func Configure(opts ...Option) error {
+	o := defaults.get()
+
+	// The default hooks are replaced by the given hooks (if any) rather than added to.
+	hooks := o.hooks
+	o.hooks, o.hooksSet = nil, false
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if !o.hooksSet {
+		o.hooks = hooks
+	}
+
+	if err := o.validate(); err != nil {
+		return err
+	}
+
+	defaults.set(func(d *options) {
+		*d = *o
+
+		// The routes are defaults, so they are replaced by the routes given to New.
+		d.routesSet = false
+	})
+
+	return nil
+}
+
+// validate returns an error if the options would result in a panic when a logger is created.
+func (o *options) validate() error {
+	if !isSupportedEncoding(o.encoding) {
+		return fmt.Errorf("unsupported encoding %q", o.encoding)
+	}
+
+	for _, r := range o.routes {
+		if r.encoding != "" && !isSupportedEncoding(r.encoding) {
+			return fmt.Errorf("unsupported encoding %q", r.encoding)
+		}
+	}
+
+	return nil
+}
+
+// ignoreModuleLevels is an internal option for the cores which apply the levels of the routes
+// but not the level of the module.
+func ignoreModuleLevels() Option {
+	return func(o *options) {
+		o.ignoreModuleLevels = true
+	}
+}
+
+type generationCore struct {
+	generation uint64
+	core       zapcore.Core
+}
+
+// coreBuilder builds the core of a logger from the options given to New and the current defaults.
+// The core is rebuilt when the defaults change.
+type coreBuilder struct {
+	module  string
+	opts    []Option
+	current atomic.Pointer[generationCore]
+	mutex   sync.Mutex
+}
+
+func (b *coreBuilder) get(generation uint64) zapcore.Core {
+	if c := b.current.Load(); c != nil && c.generation == generation {
+		return c.core
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if c := b.current.Load(); c != nil && c.generation == generation {
+		return c.core
+	}
+
+	o := getOptions(b.opts)
+
+	core := newZapCore(b.module, o)
+	if len(o.staticFields) > 0 {
+		core = core.With(o.staticFields)
+	}
+
+	b.current.Store(&generationCore{generation: generation, core: core})
+
+	return core
+}
+
+// reconfigurableCore delegates to the core that is built from the current defaults (see Configure).
+// The fields that are added with With are added to the current core when it is rebuilt.
+type reconfigurableCore struct {
+	builder *coreBuilder
+	fields  []zapcore.Field
+	current atomic.Pointer[generationCore]
+}
+
+func newReconfigurableCore(module string, opts ...Option) zapcore.Core {
+	c := &reconfigurableCore{
+		builder: &coreBuilder{module: module, opts: opts},
+	}
+
+	// The core is built immediately so that invalid options (for example, an unsupported encoding)
+	// result in a panic when the logger is created.
+	c.core()
+
+	return c
+}
+
+func (c *reconfigurableCore) core() zapcore.Core {
+	generation := defaults.generation.Load()
+
+	if current := c.current.Load(); current != nil && current.generation == generation {
+		return current.core
+	}
+
+	core := c.builder.get(generation)
+	if len(c.fields) > 0 {
+		core = core.With(c.fields)
+	}
+
+	c.current.Store(&generationCore{generation: generation, core: core})
+
+	return core
+}
+
+func (c *reconfigurableCore) Enabled(level zapcore.Level) bool {
+	return c.core().Enabled(level)
+}
+
+func (c *reconfigurableCore) With(fields []zapcore.Field) zapcore.Core {
+	return &reconfigurableCore{
+		builder: c.builder,
+		fields:  append(c.fields[:len(c.fields):len(c.fields)], fields...),
+	}
+}
+
+func (c *reconfigurableCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return c.core().Check(entry, ce)
+}
+
+func (c *reconfigurableCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	return c.core().Write(entry, fields)
+}
+
+func (c *reconfigurableCore) Sync() error {
+	return c.core().Sync()
+}
@@ -0,0 +1,81 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package log
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestConfigure(t *testing.T) {
+	const module = "configure-module"
+
+	resetDefaults(t)
+
+	SetLevel(module, INFO)
+
+	stdOut := newMockWriter()
+	stdErr := newMockWriter()
+
+	require.NoError(t, Configure(WithStdOut(stdOut), WithStdErr(stdErr)))
+
+	// The logger is created before the configuration is changed.
+	logger := New(module).With(zap.String("key", "value"))
+
+	explicitOutput := newMockWriter()
+
+	explicitLogger := New(module, WithStdOut(explicitOutput), WithEncoding(Console))
+
+	logger.Infoc(context.Background(), "Info log before")
+	require.Contains(t, stdOut.String(), `"msg":"Info log before","key":"value"`)
+
+	newStdOut := newMockWriter()
+
+	require.NoError(t, Configure(WithStdOut(newStdOut), WithEncoding(Logfmt)))
+
+	logger.Info("Info log after")
+	logger.Error("Error log after")
+
+	require.NotContains(t, stdOut.String(), "Info log after")
+	require.Contains(t, newStdOut.String(), `msg="Info log after" key=value`)
+	require.Contains(t, stdErr.String(), `msg="Error log after" key=value`)
+
+	// Options that are passed to New take precedence.
+	explicitLogger.Info("Explicit info log")
+
+	require.Contains(t, explicitOutput.String(), "INFO\t[configure-module]\tlog/configure_test.go:")
+	require.NotContains(t, newStdOut.String(), "Explicit info log")
+
+	t.Run("invalid encoding", func(t *testing.T) {
+		require.EqualError(t, Configure(WithEncoding("invalid")), `unsupported encoding "invalid"`)
+		require.EqualError(t, Configure(WithRoute(newMockWriter(), DEBUG, FATAL, WithRouteEncoding("invalid"))),
+			`unsupported encoding "invalid"`)
+
+		logger.Info("Info log after invalid configuration")
+		require.Contains(t, newStdOut.String(), `msg="Info log after invalid configuration"`)
+	})
+
+	t.Run("routes", func(t *testing.T) {
+		output := newMockWriter()
+
+		require.NoError(t, Configure(WithRoute(output, DEBUG, FATAL)))
+
+		logger.Error("Error log with route")
+		require.Contains(t, output.String(), "Error log with route")
+
+		// The configured routes are replaced by the routes of the logger.
+		routeOutput := newMockWriter()
+
+		New(module, WithRoute(routeOutput, DEBUG, FATAL)).Info("Info log with route")
+
+		require.Contains(t, routeOutput.String(), "Info log with route")
+		require.NotContains(t, output.String(), "Info log with route")
+	})
+}
@@ -0,0 +1,150 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package log
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/baggage"
+	"go.uber.org/zap"
+)
+
+// BaggageLevelKey is the default key of the baggage member which holds the log level override of a request.
+const BaggageLevelKey = "log-level"
+
+// BaggageLevelAuthorizer returns true if the log level override in the given baggage member may be applied,
+// for example if the member has a valid signature property or if the request came from a trusted caller.
+type BaggageLevelAuthorizer func(ctx context.Context, member baggage.Member) bool
+
+type baggageLevelOptions struct {
+	key       string
+	authorize BaggageLevelAuthorizer
+}
+
+// BaggageLevelOption is an option for EnableBaggageLevel.
+type BaggageLevelOption func(o *baggageLevelOptions)
+
+// WithBaggageLevelKey sets the key of the baggage member which holds the log level. The default is "log-level".
+func WithBaggageLevelKey(key string) BaggageLevelOption {
+	return func(o *baggageLevelOptions) {
+		o.key = key
+	}
+}
+
+// WithBaggageLevelAuthorizer sets a function which decides whether the log level override of a baggage member
+// is applied. The function is invoked for each context log that is not enabled by the level of the module,
+// so it should be fast (for example, cache the result of a signature verification).
+func WithBaggageLevelAuthorizer(authorize BaggageLevelAuthorizer) BaggageLevelOption {
+	return func(o *baggageLevelOptions) {
+		o.authorize = authorize
+	}
+}
+
+var baggageLevel atomic.Pointer[baggageLevelOptions] //nolint:gochecknoglobals
+
+// EnableBaggageLevel enables per-request log level overrides. If the baggage of the context which is passed
+// to Debugc (or any of the other context log functions) contains a log level member (for example, log-level=debug)
+// then entries at (or above) the given level are logged even if the level is not enabled for the module.
+// Since baggage is propagated across services, this allows a single request to be debugged everywhere it goes.
+//
+// The override can only lower the level. Since baggage may be set by any caller, consider restricting
+// overrides with WithBaggageLevelAuthorizer.
+func EnableBaggageLevel(opts ...BaggageLevelOption) {
+	o := &baggageLevelOptions{key: BaggageLevelKey}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	baggageLevel.Store(o)
+}
+
+// DisableBaggageLevel disables per-request log level overrides.
+func DisableBaggageLevel() {
+	baggageLevel.Store(nil)
+}
+
+// ContextWithBaggageLevel returns a context whose baggage contains a log level override for the request
+// (see EnableBaggageLevel).
+func ContextWithBaggageLevel(ctx context.Context, level Level) (context.Context, error) {
+	key := BaggageLevelKey
+
+	if o := baggageLevel.Load(); o != nil {
+		key = o.key
+	}
+
+	member, err := baggage.NewMember(key, level.String())
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := baggage.FromContext(ctx).SetMember(member)
+	if err != nil {
+		return nil, err
+	}
+
+	return baggage.ContextWithBaggage(ctx, b), nil
+}
+
+// contextLevel returns the log level override from the baggage of the given context,
+// or false if there's no (authorized) override or if overrides are not enabled.
+func contextLevel(ctx context.Context) (Level, bool) {
+	o := baggageLevel.Load()
+	if o == nil || ctx == nil {
+		return 0, false
+	}
+
+	member := baggage.FromContext(ctx).Member(o.key)
+	if member.Key() == "" {
+		return 0, false
+	}
+
+	level, err := ParseLevel(member.Value())
+	if err != nil {
+		return 0, false
+	}
+
+	if o.authorize != nil && !o.authorize(ctx, member) {
+		return 0, false
+	}
+
+	return level, true
+}
+
+// ctxLoggerFor returns the logger for a context log at the given level.
+func (l *Log) ctxLoggerFor(ctx context.Context, level Level) *zap.Logger {
+	if l.levels.isEnabled(l.module, level) {
+		return l.ctxLogger
+	}
+
+	if override, ok := contextLevel(ctx); ok && level >= override {
+		return l.overrideLogger.get()
+	}
+
+	return l.ctxLogger
+}
+
+// lazyLogger creates a logger on first use.
+type lazyLogger struct {
+	once   sync.Once
+	create func() *zap.Logger
+	logger *zap.Logger
+}
+
+func newLazyLogger(create func() *zap.Logger) *lazyLogger {
+	return &lazyLogger{create: create}
+}
+
+func (l *lazyLogger) get() *zap.Logger {
+	l.once.Do(func() {
+		l.logger = l.create()
+	})
+
+	return l.logger
+}
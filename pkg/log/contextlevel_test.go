@@ -0,0 +1,115 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package log
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/baggage"
+	"go.uber.org/zap"
+)
+
+func TestBaggageLevel(t *testing.T) {
+	const module = "baggage-level-module"
+
+	SetLevel(module, WARNING)
+
+	debugCtx, err := ContextWithBaggageLevel(context.Background(), DEBUG)
+	require.NoError(t, err)
+
+	t.Run("disabled", func(t *testing.T) {
+		output := newMockWriter()
+
+		logger := New(module, WithRoute(output, DEBUG, FATAL))
+
+		logger.Debugc(debugCtx, "Sample debug log")
+
+		require.Empty(t, output.String())
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		EnableBaggageLevel()
+		defer DisableBaggageLevel()
+
+		output := newMockWriter()
+
+		logger := New(module, WithRoute(output, INFO, FATAL)).With(zap.String("key", "value"))
+
+		logger.Debugc(debugCtx, "Sample debug log")
+		logger.Infoc(debugCtx, "Sample info log")
+		logger.Infoc(context.Background(), "Sample info log without override")
+		logger.Debug("Sample debug log without context")
+		logger.Warnc(debugCtx, "Sample warn log")
+
+		// The route levels still apply.
+		require.NotContains(t, output.String(), "Sample debug log")
+		require.Contains(t, output.String(), `"msg":"Sample info log","key":"value"`)
+		require.Contains(t, output.String(), `"caller":"log/contextlevel_test.go:`)
+		require.NotContains(t, output.String(), "without override")
+		require.Contains(t, output.String(), "Sample warn log")
+	})
+
+	t.Run("higher override", func(t *testing.T) {
+		EnableBaggageLevel()
+		defer DisableBaggageLevel()
+
+		output := newMockWriter()
+
+		logger := New(module, WithRoute(output, DEBUG, FATAL))
+
+		errorCtx, err := ContextWithBaggageLevel(context.Background(), ERROR)
+		require.NoError(t, err)
+
+		logger.Infoc(errorCtx, "Sample info log")
+		logger.Warnc(errorCtx, "Sample warn log")
+
+		require.NotContains(t, output.String(), "Sample info log")
+		require.Contains(t, output.String(), "Sample warn log")
+	})
+
+	t.Run("custom key and authorizer", func(t *testing.T) {
+		EnableBaggageLevel(
+			WithBaggageLevelKey("debug-level"),
+			WithBaggageLevelAuthorizer(func(_ context.Context, member baggage.Member) bool {
+				return len(member.Properties()) > 0 && member.Properties()[0].Key() == "sig"
+			}),
+		)
+		defer DisableBaggageLevel()
+
+		output := newMockWriter()
+
+		logger := New(module, WithRoute(output, DEBUG, FATAL))
+
+		unsignedCtx, err := ContextWithBaggageLevel(context.Background(), DEBUG)
+		require.NoError(t, err)
+
+		logger.Debugc(unsignedCtx, "Unsigned debug log")
+		logger.Debugc(debugCtx, "Wrong key debug log")
+
+		b, err := baggage.Parse("debug-level=debug;sig=abc")
+		require.NoError(t, err)
+
+		logger.Debugc(baggage.ContextWithBaggage(context.Background(), b), "Signed debug log")
+
+		require.NotContains(t, output.String(), "Unsigned debug log")
+		require.NotContains(t, output.String(), "Wrong key debug log")
+		require.Contains(t, output.String(), "Signed debug log")
+	})
+
+	t.Run("invalid level", func(t *testing.T) {
+		EnableBaggageLevel()
+		defer DisableBaggageLevel()
+
+		b, err := baggage.Parse(BaggageLevelKey + "=verbose")
+		require.NoError(t, err)
+
+		_, ok := contextLevel(baggage.ContextWithBaggage(context.Background(), b))
+		require.False(t, ok)
+	})
+}
@@ -0,0 +1,390 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package log
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Vendor log encodings. These are JSON encodings that use the key names and formats
+// expected by the respective log platform.
+const (
+	// GCP is the Google Cloud Logging encoding. The trace is output with the key 'logging.googleapis.com/trace'.
+	// If the GOOGLE_CLOUD_PROJECT environment variable is set then the trace is formatted as
+	// projects/<project>/traces/<trace-id>, which allows Cloud Logging to correlate logs with Cloud Trace.
+	GCP Encoding = "gcp"
+	// ECS is the Elastic Common Schema encoding.
+	ECS Encoding = "ecs"
+	// Datadog is the Datadog encoding. Trace and span IDs are output in decimal format ('dd.trace_id', 'dd.span_id').
+	Datadog Encoding = "datadog"
+)
+
+// OmitKey may be used as the value of a key in EncoderKeys in order to omit the field from the output.
+const OmitKey = "-"
+
+const gcpProjectEnv = "GOOGLE_CLOUD_PROJECT"
+
+// EncoderKeys defines the keys of the standard log fields. Empty keys are not overridden.
+type EncoderKeys struct {
+	Time          string `json:"time,omitempty" yaml:"time,omitempty"`
+	Level         string `json:"level,omitempty" yaml:"level,omitempty"`
+	Logger        string `json:"logger,omitempty" yaml:"logger,omitempty"`
+	Caller        string `json:"caller,omitempty" yaml:"caller,omitempty"`
+	Message       string `json:"message,omitempty" yaml:"message,omitempty"`
+	Stacktrace    string `json:"stacktrace,omitempty" yaml:"stacktrace,omitempty"`
+	TraceID       string `json:"traceID,omitempty" yaml:"traceID,omitempty"`
+	SpanID        string `json:"spanID,omitempty" yaml:"spanID,omitempty"`
+	ParentSpanID  string `json:"parentSpanID,omitempty" yaml:"parentSpanID,omitempty"`
+	CorrelationID string `json:"correlationID,omitempty" yaml:"correlationID,omitempty"`
+}
+
+// WithEncoderKeys overrides the keys of the standard log fields (on top of the keys of the encoding).
+//
+// Example:
+//
+//	log.New("module1", log.WithEncoding(log.JSON), log.WithEncoderKeys(log.EncoderKeys{Time: "@t", Caller: log.OmitKey}))
+func WithEncoderKeys(keys EncoderKeys) Option {
+	return func(o *options) {
+		o.encoderKeys = &keys
+	}
+}
+
+// merge returns the keys with the non-empty keys of the given overrides applied.
+func (k EncoderKeys) merge(overrides *EncoderKeys) EncoderKeys {
+	if overrides == nil {
+		return k
+	}
+
+	override := func(key *string, value string) {
+		if value != "" {
+			*key = value
+		}
+	}
+
+	override(&k.Time, overrides.Time)
+	override(&k.Level, overrides.Level)
+	override(&k.Logger, overrides.Logger)
+	override(&k.Caller, overrides.Caller)
+	override(&k.Message, overrides.Message)
+	override(&k.Stacktrace, overrides.Stacktrace)
+	override(&k.TraceID, overrides.TraceID)
+	override(&k.SpanID, overrides.SpanID)
+	override(&k.ParentSpanID, overrides.ParentSpanID)
+	override(&k.CorrelationID, overrides.CorrelationID)
+
+	return k
+}
+
+// encodingPreset defines the keys and formats of an encoding.
+type encodingPreset struct {
+	newEncoder  func(cfg zapcore.EncoderConfig) zapcore.Encoder
+	keys        EncoderKeys
+	encodeLevel zapcore.LevelEncoder
+	encodeTime  zapcore.TimeEncoder
+	encodeName  zapcore.NameEncoder
+	tracing     tracingFormat
+}
+
+var defaultKeys = EncoderKeys{ //nolint:gochecknoglobals
+	Time:          timestampKey,
+	Level:         levelKey,
+	Logger:        moduleKey,
+	Caller:        callerKey,
+	Message:       messageKey,
+	Stacktrace:    stacktraceKey,
+	TraceID:       FieldTraceID,
+	SpanID:        FieldSpanID,
+	ParentSpanID:  FieldParentSpanID,
+	CorrelationID: FieldCorrelationID,
+}
+
+func getEncodingPreset(encoding Encoding) (*encodingPreset, bool) {
+	switch strings.ToLower(encoding) {
+	case JSON:
+		return &encodingPreset{
+			newEncoder:  zapcore.NewJSONEncoder,
+			keys:        defaultKeys,
+			encodeLevel: lowercaseLevelEncoder,
+			encodeTime:  zapcore.ISO8601TimeEncoder,
+		}, true
+	case Logfmt:
+		return &encodingPreset{
+			newEncoder:  newLogfmtEncoder,
+			keys:        defaultKeys,
+			encodeLevel: lowercaseLevelEncoder,
+			encodeTime:  zapcore.ISO8601TimeEncoder,
+		}, true
+	case Pretty:
+		return &encodingPreset{
+			newEncoder:  newPrettyEncoder,
+			keys:        defaultKeys,
+			encodeLevel: capitalLevelEncoder,
+			encodeTime:  zapcore.TimeEncoderOfLayout(prettyTimeLayout),
+		}, true
+	case Console:
+		return &encodingPreset{
+			newEncoder:  zapcore.NewConsoleEncoder,
+			keys:        defaultKeys,
+			encodeLevel: capitalLevelEncoder,
+			encodeTime:  zapcore.ISO8601TimeEncoder,
+			encodeName: func(moduleName string, encoder zapcore.PrimitiveArrayEncoder) {
+				encoder.AppendString(fmt.Sprintf("[%s]", moduleName))
+			},
+		}, true
+	case GCP:
+		return gcpPreset(), true
+	case ECS:
+		return ecsPreset(), true
+	case Datadog:
+		return datadogPreset(), true
+	default:
+		return nil, false
+	}
+}
+
+func gcpPreset() *encodingPreset {
+	keys := defaultKeys
+	keys.Time = "time"
+	keys.Level = "severity"
+	keys.Message = "message"
+	keys.Stacktrace = "stack_trace"
+	keys.TraceID = "logging.googleapis.com/trace"
+	keys.SpanID = "logging.googleapis.com/spanId"
+
+	formatTraceID := func(sc trace.SpanContext) string {
+		return sc.TraceID().String()
+	}
+
+	if project := os.Getenv(gcpProjectEnv); project != "" {
+		formatTraceID = func(sc trace.SpanContext) string {
+			return "projects/" + project + "/traces/" + sc.TraceID().String()
+		}
+	}
+
+	return &encodingPreset{
+		keys:        keys,
+		encodeLevel: gcpLevelEncoder,
+		encodeTime:  zapcore.RFC3339NanoTimeEncoder,
+		tracing: tracingFormat{
+			traceID:    formatTraceID,
+			sampledKey: "logging.googleapis.com/trace_sampled",
+		},
+	}
+}
+
+func ecsPreset() *encodingPreset {
+	keys := defaultKeys
+	keys.Time = "@timestamp"
+	keys.Level = "log.level"
+	keys.Logger = "log.logger"
+	keys.Caller = "log.origin.file.name"
+	keys.Message = "message"
+	keys.Stacktrace = "error.stack_trace"
+	keys.TraceID = "trace.id"
+	keys.SpanID = "span.id"
+	keys.ParentSpanID = "parent.id"
+
+	return &encodingPreset{
+		keys:        keys,
+		encodeLevel: lowercaseLevelEncoder,
+		encodeTime:  zapcore.ISO8601TimeEncoder,
+	}
+}
+
+func datadogPreset() *encodingPreset {
+	keys := defaultKeys
+	keys.Time = "timestamp"
+	keys.Level = "status"
+	keys.Logger = "logger.name"
+	keys.Message = "message"
+	keys.Stacktrace = "error.stack"
+	keys.TraceID = "dd.trace_id"
+	keys.SpanID = "dd.span_id"
+
+	return &encodingPreset{
+		keys:        keys,
+		encodeLevel: lowercaseLevelEncoder,
+		encodeTime:  zapcore.RFC3339NanoTimeEncoder,
+		tracing: tracingFormat{
+			// Datadog uses the lower 64 bits of the trace ID in decimal format.
+			traceID: func(sc trace.SpanContext) string {
+				traceID := sc.TraceID()
+
+				return strconv.FormatUint(binary.BigEndian.Uint64(traceID[8:]), 10)
+			},
+			spanID: func(spanID trace.SpanID) string {
+				return strconv.FormatUint(binary.BigEndian.Uint64(spanID[:]), 10)
+			},
+		},
+	}
+}
+
+// lowercaseLevelEncoder encodes the level in lowercase. Unlike zapcore.LowercaseLevelEncoder, it supports
+// the TRACE level and encodes zap's DPanic level as CRITICAL.
+func lowercaseLevelEncoder(l zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+	switch Level(l) {
+	case TRACE:
+		enc.AppendString("trace")
+	case CRITICAL:
+		enc.AppendString("critical")
+	default:
+		zapcore.LowercaseLevelEncoder(l, enc)
+	}
+}
+
+// capitalLevelEncoder encodes the level in uppercase. Unlike zapcore.CapitalLevelEncoder, it supports
+// the TRACE level and encodes zap's DPanic level as CRITICAL.
+func capitalLevelEncoder(l zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+	enc.AppendString(Level(l).String())
+}
+
+// gcpLevelEncoder encodes the level as a Google Cloud Logging severity.
+func gcpLevelEncoder(l zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+	switch l {
+	case zapcore.Level(TRACE), zapcore.DebugLevel:
+		enc.AppendString("DEBUG")
+	case zapcore.InfoLevel:
+		enc.AppendString("INFO")
+	case zapcore.WarnLevel:
+		enc.AppendString("WARNING")
+	case zapcore.ErrorLevel:
+		enc.AppendString("ERROR")
+	case zapcore.DPanicLevel:
+		enc.AppendString("CRITICAL")
+	case zapcore.PanicLevel:
+		enc.AppendString("ALERT")
+	case zapcore.FatalLevel:
+		enc.AppendString("EMERGENCY")
+	default:
+		enc.AppendString("DEFAULT")
+	}
+}
+
+// encoderConfig returns the zap encoder config for the preset with the given overrides applied.
+func (p *encodingPreset) encoderConfig(timeEncoder zapcore.TimeEncoder, overrides *EncoderKeys) zapcore.EncoderConfig {
+	keys := p.keys.merge(overrides)
+
+	if timeEncoder == nil {
+		timeEncoder = p.encodeTime
+	}
+
+	return zapcore.EncoderConfig{
+		TimeKey:        encoderKey(keys.Time),
+		LevelKey:       encoderKey(keys.Level),
+		NameKey:        encoderKey(keys.Logger),
+		CallerKey:      encoderKey(keys.Caller),
+		FunctionKey:    zapcore.OmitKey,
+		MessageKey:     encoderKey(keys.Message),
+		StacktraceKey:  encoderKey(keys.Stacktrace),
+		LineEnding:     zapcore.DefaultLineEnding,
+		EncodeLevel:    p.encodeLevel,
+		EncodeTime:     timeEncoder,
+		EncodeDuration: zapcore.StringDurationEncoder,
+		EncodeCaller:   zapcore.ShortCallerEncoder,
+		EncodeName:     p.encodeName,
+	}
+}
+
+// tracingFormatOf returns the format of the tracing fields for the given encoding and overrides,
+// or nil if the default format is used.
+func tracingFormatOf(encoding Encoding, overrides *EncoderKeys) *tracingFormat {
+	preset, ok := getEncodingPreset(encoding)
+	if !ok {
+		return nil
+	}
+
+	return preset.tracingFormat(overrides)
+}
+
+// tracingFormat returns the format of the tracing fields for the preset with the given overrides applied,
+// or nil if the default format is used.
+func (p *encodingPreset) tracingFormat(overrides *EncoderKeys) *tracingFormat {
+	keys := p.keys.merge(overrides)
+
+	f := p.tracing
+	f.traceIDKey = encoderKey(keys.TraceID)
+	f.spanIDKey = encoderKey(keys.SpanID)
+	f.parentSpanIDKey = encoderKey(keys.ParentSpanID)
+	f.correlationIDKey = encoderKey(keys.CorrelationID)
+
+	if f.isDefault() {
+		return nil
+	}
+
+	return &f
+}
+
+func encoderKey(key string) string {
+	if key == OmitKey {
+		return zapcore.OmitKey
+	}
+
+	return key
+}
+
+// tracingFormat defines the keys and formats of the fields that are added by WithTracing.
+type tracingFormat struct {
+	traceIDKey       string
+	spanIDKey        string
+	parentSpanIDKey  string
+	correlationIDKey string
+	sampledKey       string
+	traceID          func(sc trace.SpanContext) string
+	spanID           func(spanID trace.SpanID) string
+}
+
+var defaultTracingFormat = &tracingFormat{ //nolint:gochecknoglobals
+	traceIDKey:       FieldTraceID,
+	spanIDKey:        FieldSpanID,
+	parentSpanIDKey:  FieldParentSpanID,
+	correlationIDKey: FieldCorrelationID,
+}
+
+func (f *tracingFormat) isDefault() bool {
+	return f.traceIDKey == FieldTraceID && f.spanIDKey == FieldSpanID &&
+		f.parentSpanIDKey == FieldParentSpanID && f.correlationIDKey == FieldCorrelationID &&
+		f.sampledKey == "" && f.traceID == nil && f.spanID == nil
+}
+
+func (f *tracingFormat) formatTraceID(sc trace.SpanContext) string {
+	if f.traceID != nil {
+		return f.traceID(sc)
+	}
+
+	return sc.TraceID().String()
+}
+
+func (f *tracingFormat) formatSpanID(spanID trace.SpanID) string {
+	if f.spanID != nil {
+		return f.spanID(spanID)
+	}
+
+	return spanID.String()
+}
+
+// newTracingFormatCore wraps the given core so that the fields added by WithTracing are
+// output using the given format.
+func newTracingFormatCore(core zapcore.Core, format *tracingFormat) zapcore.Core {
+	return newTransformCore(core, func(fields []zapcore.Field) []zapcore.Field {
+		return replaceFields(fields, func(field zapcore.Field) (zapcore.Field, bool) {
+			m, ok := field.Interface.(*otelMarshaller)
+			if !ok {
+				return field, false
+			}
+
+			return zap.Inline(&otelMarshaller{ctx: m.ctx, format: format}), true
+		})
+	})
+}
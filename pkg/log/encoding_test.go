@@ -0,0 +1,215 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package log
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/trustbloc/logutil-go/pkg/log/mocks"
+)
+
+func TestEncodingPresets(t *testing.T) {
+	const module = "encoding-module"
+
+	tracer := trace.NewTracerProvider().Tracer("unit-test")
+
+	ctx, span := tracer.Start(context.Background(), "parent-span")
+	defer span.End()
+
+	ctx, childSpan := tracer.Start(ctx, "child-span")
+	defer childSpan.End()
+
+	sc := childSpan.SpanContext()
+
+	t.Run("GCP", func(t *testing.T) {
+		t.Setenv(gcpProjectEnv, "my-project")
+
+		entries := logEntries(t, module, GCP, func(l *Log) {
+			l.Warnc(ctx, "Sample warn log")
+			l.Error("Sample error log")
+		})
+
+		require.Equal(t, "WARNING", entries[0]["severity"])
+		require.Equal(t, "Sample warn log", entries[0]["message"])
+		require.Equal(t, module, entries[0]["logger"])
+		require.NotEmpty(t, entries[0]["time"])
+		require.Equal(t, "projects/my-project/traces/"+sc.TraceID().String(),
+			entries[0]["logging.googleapis.com/trace"])
+		require.Equal(t, sc.SpanID().String(), entries[0]["logging.googleapis.com/spanId"])
+		require.Equal(t, true, entries[0]["logging.googleapis.com/trace_sampled"])
+		require.NotContains(t, entries[0], "trace_id")
+		require.NotContains(t, entries[0], "msg")
+
+		require.Equal(t, "ERROR", entries[1]["severity"])
+	})
+
+	t.Run("GCP without project", func(t *testing.T) {
+		entries := logEntries(t, module, GCP, func(l *Log) {
+			l.Infoc(ctx, "Sample info log")
+		})
+
+		require.Equal(t, sc.TraceID().String(), entries[0]["logging.googleapis.com/trace"])
+	})
+
+	t.Run("ECS", func(t *testing.T) {
+		entries := logEntries(t, module, ECS, func(l *Log) {
+			l.Infoc(ctx, "Sample info log")
+		})
+
+		require.Equal(t, "info", entries[0]["log.level"])
+		require.Equal(t, "Sample info log", entries[0]["message"])
+		require.Equal(t, module, entries[0]["log.logger"])
+		require.NotEmpty(t, entries[0]["@timestamp"])
+		require.Contains(t, entries[0]["log.origin.file.name"], "log/encoding_test.go")
+		require.Equal(t, sc.TraceID().String(), entries[0]["trace.id"])
+		require.Equal(t, sc.SpanID().String(), entries[0]["span.id"])
+		require.Equal(t, span.SpanContext().SpanID().String(), entries[0]["parent.id"])
+	})
+
+	t.Run("Datadog", func(t *testing.T) {
+		entries := logEntries(t, module, Datadog, func(l *Log) {
+			l.Infoc(ctx, "Sample info log")
+		})
+
+		traceID := sc.TraceID()
+		spanID := sc.SpanID()
+
+		require.Equal(t, "info", entries[0]["status"])
+		require.Equal(t, "Sample info log", entries[0]["message"])
+		require.Equal(t, module, entries[0]["logger.name"])
+		require.NotEmpty(t, entries[0]["timestamp"])
+		require.Equal(t, strconv.FormatUint(binary.BigEndian.Uint64(traceID[8:]), 10), entries[0]["dd.trace_id"])
+		require.Equal(t, strconv.FormatUint(binary.BigEndian.Uint64(spanID[:]), 10), entries[0]["dd.span_id"])
+	})
+
+	t.Run("custom keys", func(t *testing.T) {
+		entries := logEntries(t, module, JSON, func(l *Log) {
+			l.Infoc(ctx, "Sample info log")
+		}, WithEncoderKeys(EncoderKeys{
+			Time:         "@t",
+			Caller:       OmitKey,
+			Message:      "text",
+			TraceID:      "traceId",
+			ParentSpanID: OmitKey,
+		}))
+
+		require.NotEmpty(t, entries[0]["@t"])
+		require.NotContains(t, entries[0], "ts")
+		require.NotContains(t, entries[0], "caller")
+		require.Equal(t, "Sample info log", entries[0]["text"])
+		require.Equal(t, "info", entries[0]["level"])
+		require.Equal(t, sc.TraceID().String(), entries[0]["traceId"])
+		require.Equal(t, sc.SpanID().String(), entries[0]["span_id"])
+		require.NotContains(t, entries[0], "parent_span_id")
+	})
+
+	t.Run("custom keys on top of preset", func(t *testing.T) {
+		entries := logEntries(t, module, ECS, func(l *Log) {
+			l.Info("Sample info log")
+		}, WithEncoderKeys(EncoderKeys{Level: "severity"}))
+
+		require.Equal(t, "info", entries[0]["severity"])
+		require.Equal(t, "Sample info log", entries[0]["message"])
+	})
+
+	t.Run("stacktrace key", func(t *testing.T) {
+		preset, ok := getEncodingPreset(ECS)
+		require.True(t, ok)
+
+		require.Equal(t, "error.stack_trace", preset.encoderConfig(nil, nil).StacktraceKey)
+		require.Equal(t, "stack", preset.encoderConfig(nil, &EncoderKeys{Stacktrace: "stack"}).StacktraceKey)
+		require.Empty(t, preset.encoderConfig(nil, &EncoderKeys{Stacktrace: OmitKey}).StacktraceKey)
+	})
+
+	t.Run("default format", func(t *testing.T) {
+		require.Nil(t, tracingFormatOf(JSON, nil))
+		require.Nil(t, tracingFormatOf(Console, &EncoderKeys{Time: "t"}))
+		require.NotNil(t, tracingFormatOf(JSON, &EncoderKeys{SpanID: "spanId"}))
+		require.Nil(t, tracingFormatOf("invalid", nil))
+	})
+
+	t.Run("no span", func(t *testing.T) {
+		entries := logEntries(t, module, Datadog, func(l *Log) {
+			l.Infoc(oteltrace.ContextWithSpanContext(context.Background(), oteltrace.SpanContext{}), "Sample info log")
+		})
+
+		require.NotContains(t, entries[0], "dd.trace_id")
+	})
+}
+
+func TestGCPLevelEncoder(t *testing.T) {
+	for level, expected := range map[zapcore.Level]string{
+		zapcore.DebugLevel:   "DEBUG",
+		zapcore.InfoLevel:    "INFO",
+		zapcore.WarnLevel:    "WARNING",
+		zapcore.ErrorLevel:   "ERROR",
+		zapcore.DPanicLevel:  "CRITICAL",
+		zapcore.PanicLevel:   "ALERT",
+		zapcore.FatalLevel:   "EMERGENCY",
+		zapcore.Level(TRACE): "DEBUG",
+		zapcore.Level(100):   "DEFAULT",
+	} {
+		enc := mocks.NewArrayEncoder()
+
+		gcpLevelEncoder(level, enc)
+
+		require.Equal(t, []interface{}{expected}, enc.Items())
+	}
+}
+
+func TestLevelEncoders(t *testing.T) {
+	for level, expected := range map[Level]string{
+		TRACE:    "trace",
+		DEBUG:    "debug",
+		WARNING:  "warn",
+		CRITICAL: "critical",
+		FATAL:    "fatal",
+	} {
+		enc := mocks.NewArrayEncoder()
+
+		lowercaseLevelEncoder(zapcore.Level(level), enc)
+		capitalLevelEncoder(zapcore.Level(level), enc)
+
+		require.Equal(t, []interface{}{expected, strings.ToUpper(expected)}, enc.Items())
+	}
+}
+
+// logEntries creates a logger with the given encoding, invokes the given function and returns the decoded entries.
+func logEntries(t *testing.T, module string, encoding Encoding, log func(l *Log), opts ...Option) []map[string]interface{} {
+	t.Helper()
+
+	SetLevel(module, DEBUG)
+
+	output := newMockWriter()
+
+	log(New(module, append([]Option{WithEncoding(encoding), WithRoute(output, DEBUG, FATAL)}, opts...)...))
+
+	var entries []map[string]interface{}
+
+	decoder := json.NewDecoder(output)
+
+	for decoder.More() {
+		entry := make(map[string]interface{})
+		require.NoError(t, decoder.Decode(&entry))
+
+		entries = append(entries, entry)
+	}
+
+	require.NotEmpty(t, entries)
+
+	return entries
+}
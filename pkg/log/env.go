@@ -0,0 +1,233 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package log
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Environment variables that are read by ConfigureFromEnv.
+const (
+	// EnvEncoding is the environment variable for the log encoding (see WithEncoding).
+	EnvEncoding = "LOG_ENCODING"
+	// EnvLevel is the environment variable for the log level spec (see SetSpec).
+	EnvLevel = "LOG_LEVEL"
+	// EnvOutput is the environment variable for the output of TRACE, DEBUG, INFO, and WARN logs. The value may be
+	// 'stdout', 'stderr' or a file path.
+	EnvOutput = "LOG_OUTPUT"
+	// EnvErrorOutput is the environment variable for the output of ERROR, CRITICAL, PANIC, and FATAL logs.
+	// The value may be 'stdout', 'stderr' or a file path.
+	EnvErrorOutput = "LOG_ERROR_OUTPUT"
+	// EnvCallerSkip is the environment variable for the caller skip of the context logger.
+	EnvCallerSkip = "LOG_CALLER_SKIP"
+	// EnvTimeFormat is the environment variable for the timestamp format (see WithTimeFormat).
+	EnvTimeFormat = "LOG_TIME_FORMAT"
+)
+
+// ConfigureFromEnv configures the default logging options and log levels from the following environment
+// variables: LOG_ENCODING, LOG_LEVEL, LOG_OUTPUT, LOG_ERROR_OUTPUT, LOG_CALLER_SKIP, and LOG_TIME_FORMAT.
+// Variables that are not set (or are empty) are ignored.
+//
+// The log levels are applied immediately. The remaining options replace the defaults of all loggers,
+// including the loggers that were already created (see Configure), unless the logger overrides the
+// option explicitly. The caller skip only applies to loggers that are created afterwards. Output files that were
+// opened by a previous call are closed once they are replaced.
+//
+// All variables are validated before any of them is applied. If one or more variables are invalid
+// then an error that describes each invalid variable is returned and nothing is changed.
+func ConfigureFromEnv() error {
+	return configureFromEnv(os.LookupEnv)
+}
+
+type envConfig struct {
+	encoding    Encoding
+	stdOut      zapcore.WriteSyncer
+	stdErr      zapcore.WriteSyncer
+	callerSkip  *int
+	timeEncoder zapcore.TimeEncoder
+	// opened contains the outputs which were opened for LOG_OUTPUT and LOG_ERROR_OUTPUT.
+	opened []*openedOutput
+
+	levelSpec        bool
+	defaultLevel     Level
+	moduleLevelPairs []moduleLevelPair
+}
+
+func configureFromEnv(lookupEnv func(string) (string, bool)) error {
+	cfg, err := parseEnv(lookupEnv)
+	if err != nil {
+		return err
+	}
+
+	// The outputs which were opened by a previous call are closed once they are replaced.
+	defaults.setOutputs(func(o *options) {
+		if cfg.encoding != "" {
+			o.encoding = cfg.encoding
+		}
+
+		if cfg.stdOut != nil {
+			o.stdOut = cfg.stdOut
+			o.routes = nil
+		}
+
+		if cfg.stdErr != nil {
+			o.stdErr = cfg.stdErr
+			o.routes = nil
+		}
+
+		if cfg.callerSkip != nil {
+			o.callerSkip = *cfg.callerSkip
+		}
+
+		if cfg.timeEncoder != nil {
+			o.timeEncoder = cfg.timeEncoder
+		}
+	}, cfg.opened)
+
+	if cfg.levelSpec {
+		levels.setSpec(cfg.defaultLevel, cfg.moduleLevelPairs)
+	}
+
+	return nil
+}
+
+//nolint:cyclop
+func parseEnv(lookupEnv func(string) (string, bool)) (*envConfig, error) {
+	getEnv := func(name string) string {
+		value, _ := lookupEnv(name)
+
+		return strings.TrimSpace(value)
+	}
+
+	cfg := &envConfig{}
+
+	var errs []error
+
+	if value := getEnv(EnvEncoding); value != "" {
+		if isSupportedEncoding(value) {
+			cfg.encoding = strings.ToLower(value)
+		} else {
+			errs = append(errs, fmt.Errorf("%s: unsupported encoding %q", EnvEncoding, value))
+		}
+	}
+
+	if value := getEnv(EnvLevel); value != "" {
+		defaultLevel, moduleLevelPairs, err := parseSpec(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: invalid log level spec %q: %w", EnvLevel, value, err))
+		} else {
+			cfg.levelSpec = true
+			cfg.defaultLevel = defaultLevel
+			cfg.moduleLevelPairs = moduleLevelPairs
+		}
+	}
+
+	if value := getEnv(EnvCallerSkip); value != "" {
+		callerSkip, err := strconv.Atoi(value)
+		if err != nil || callerSkip < 0 {
+			errs = append(errs, fmt.Errorf("%s: invalid caller skip %q: must be a non-negative integer",
+				EnvCallerSkip, value))
+		} else {
+			cfg.callerSkip = &callerSkip
+		}
+	}
+
+	if value := getEnv(EnvTimeFormat); value != "" {
+		timeEncoder, err := parseTimeFormat(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", EnvTimeFormat, err))
+		} else {
+			cfg.timeEncoder = timeEncoder
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	// Outputs are opened last so that no files are created if any of the other variables are invalid.
+	if value := getEnv(EnvOutput); value != "" {
+		stdOut, closeOutput, err := openOutput(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", EnvOutput, err))
+		} else {
+			cfg.stdOut = stdOut
+			cfg.opened = append(cfg.opened, &openedOutput{output: stdOut, close: closeOutput})
+		}
+	}
+
+	if value := getEnv(EnvErrorOutput); value != "" {
+		stdErr, closeOutput, err := openOutput(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", EnvErrorOutput, err))
+		} else {
+			cfg.stdErr = stdErr
+			cfg.opened = append(cfg.opened, &openedOutput{output: stdErr, close: closeOutput})
+		}
+	}
+
+	if len(errs) > 0 {
+		closeOutputs(cfg.opened)
+
+		return nil, errors.Join(errs...)
+	}
+
+	return cfg, nil
+}
+
+// openOutput opens the given output which may be 'stdout', 'stderr' or a file path, and returns a function
+// which closes it.
+// Files are opened in append mode and are created if they don't exist.
+func openOutput(output string) (zapcore.WriteSyncer, func(), error) {
+	switch output {
+	case "stdout":
+		return os.Stdout, func() {}, nil
+	case "stderr":
+		return os.Stderr, func() {}, nil
+	}
+
+	ws, closeOutput, err := zap.Open(output)
+	if err != nil {
+		return nil, nil, fmt.Errorf("open output %q: %w", output, err)
+	}
+
+	return ws, closeOutput, nil
+}
+
+// parseTimeFormat returns the time encoder for the given format. The format may be one of: iso8601,
+// rfc3339, rfc3339nano, epoch, millis, nanos, or a Go time layout.
+func parseTimeFormat(format string) (zapcore.TimeEncoder, error) {
+	switch strings.ToLower(format) {
+	case "iso8601", "iso":
+		return zapcore.ISO8601TimeEncoder, nil
+	case "rfc3339":
+		return zapcore.RFC3339TimeEncoder, nil
+	case "rfc3339nano":
+		return zapcore.RFC3339NanoTimeEncoder, nil
+	case "epoch":
+		return zapcore.EpochTimeEncoder, nil
+	case "millis":
+		return zapcore.EpochMillisTimeEncoder, nil
+	case "nanos":
+		return zapcore.EpochNanosTimeEncoder, nil
+	}
+
+	// A layout must contain at least one element of the reference time.
+	if time.Unix(0, 0).UTC().Format(format) == format {
+		return nil, fmt.Errorf("invalid time format %q", format)
+	}
+
+	return zapcore.TimeEncoderOfLayout(format), nil
+}
@@ -0,0 +1,179 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigureFromEnv(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		resetDefaults(t)
+		resetLoggingLevels()
+		t.Cleanup(resetLoggingLevels)
+
+		outFile := filepath.Join(t.TempDir(), "out.log")
+
+		t.Setenv(EnvEncoding, "Console")
+		t.Setenv(EnvLevel, "env-module1=debug:env-module2=error:warn")
+		t.Setenv(EnvOutput, outFile)
+		t.Setenv(EnvErrorOutput, "stdout")
+		t.Setenv(EnvCallerSkip, "0")
+		t.Setenv(EnvTimeFormat, "2006/01/02")
+
+		require.NoError(t, ConfigureFromEnv())
+
+		require.Equal(t, DEBUG, GetLevel("env-module1"))
+		require.Equal(t, ERROR, GetLevel("env-module2"))
+		require.Equal(t, WARNING, GetLevel(""))
+
+		o := getOptions(nil)
+		require.Equal(t, Console, o.encoding)
+		require.Equal(t, 0, o.callerSkip)
+		require.Equal(t, os.Stdout, o.stdErr)
+
+		logger := New("env-module1")
+		logger.Debug("Sample debug log")
+
+		contents, err := os.ReadFile(outFile) //nolint:gosec
+		require.NoError(t, err)
+		require.Contains(t, string(contents), "[env-module1]")
+		require.Contains(t, string(contents), "Sample debug log")
+		require.Regexp(t, `^\d{4}/\d{2}/\d{2}\s`, string(contents))
+
+		// Explicit options take precedence over the environment.
+		require.Equal(t, JSON, getOptions([]Option{WithEncoding(JSON)}).encoding)
+	})
+
+	t.Run("not set", func(t *testing.T) {
+		resetDefaults(t)
+
+		require.NoError(t, configureFromEnv(func(string) (string, bool) { return "", false }))
+
+		o := getOptions(nil)
+		require.Equal(t, DefaultEncoding, o.encoding)
+		require.Equal(t, 1, o.callerSkip)
+		require.Equal(t, os.Stdout, o.stdOut)
+		require.Equal(t, os.Stderr, o.stdErr)
+	})
+
+	t.Run("invalid values", func(t *testing.T) {
+		resetDefaults(t)
+		resetLoggingLevels()
+
+		env := map[string]string{
+			EnvEncoding:   "xml",
+			EnvLevel:      "module1=verbose",
+			EnvCallerSkip: "-1",
+			EnvTimeFormat: "yesterday",
+			EnvOutput:     filepath.Join(t.TempDir(), "out.log"),
+		}
+
+		err := configureFromEnv(func(name string) (string, bool) {
+			value, ok := env[name]
+
+			return value, ok
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), `LOG_ENCODING: unsupported encoding "xml"`)
+		require.Contains(t, err.Error(), `LOG_LEVEL: invalid log level spec "module1=verbose"`)
+		require.Contains(t, err.Error(), `LOG_CALLER_SKIP: invalid caller skip "-1"`)
+		require.Contains(t, err.Error(), `LOG_TIME_FORMAT: invalid time format "yesterday"`)
+
+		// Nothing should have been applied.
+		require.Equal(t, INFO, GetLevel("module1"))
+		require.Equal(t, DefaultEncoding, getOptions(nil).encoding)
+		require.NoFileExists(t, env[EnvOutput])
+	})
+
+	t.Run("invalid output", func(t *testing.T) {
+		resetDefaults(t)
+
+		t.Setenv(EnvErrorOutput, filepath.Join(t.TempDir(), "missing", "err.log"))
+
+		err := ConfigureFromEnv()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "LOG_ERROR_OUTPUT: open output")
+	})
+
+	t.Run("output is closed if the error output is invalid", func(t *testing.T) {
+		resetDefaults(t)
+
+		fds, err := os.ReadDir("/proc/self/fd")
+		if err != nil {
+			t.Skip("open files can't be counted:", err)
+		}
+
+		t.Setenv(EnvOutput, filepath.Join(t.TempDir(), "out.log"))
+		t.Setenv(EnvErrorOutput, filepath.Join(t.TempDir(), "missing", "err.log"))
+
+		require.Error(t, ConfigureFromEnv())
+
+		fdsAfter, err := os.ReadDir("/proc/self/fd")
+		require.NoError(t, err)
+		require.Len(t, fdsAfter, len(fds))
+	})
+
+	t.Run("replaced outputs are closed", func(t *testing.T) {
+		resetDefaults(t)
+
+		fds, err := os.ReadDir("/proc/self/fd")
+		if err != nil {
+			t.Skip("open files can't be counted:", err)
+		}
+
+		for i := 0; i < 3; i++ {
+			t.Setenv(EnvOutput, filepath.Join(t.TempDir(), "out.log"))
+			t.Setenv(EnvErrorOutput, filepath.Join(t.TempDir(), "err.log"))
+
+			require.NoError(t, ConfigureFromEnv())
+		}
+
+		fdsAfter, err := os.ReadDir("/proc/self/fd")
+		require.NoError(t, err)
+		require.Len(t, fdsAfter, len(fds)+2)
+
+		// The error output is still used, so only the output is closed.
+		t.Setenv(EnvOutput, "stdout")
+		t.Setenv(EnvErrorOutput, "")
+
+		require.NoError(t, ConfigureFromEnv())
+
+		fdsAfter, err = os.ReadDir("/proc/self/fd")
+		require.NoError(t, err)
+		require.Len(t, fdsAfter, len(fds)+1)
+	})
+}
+
+func TestParseTimeFormat(t *testing.T) {
+	for _, format := range []string{"iso8601", "ISO", "rfc3339", "rfc3339nano", "epoch", "millis", "nanos", "15:04"} {
+		timeEncoder, err := parseTimeFormat(format)
+		require.NoError(t, err)
+		require.NotNil(t, timeEncoder)
+	}
+
+	_, err := parseTimeFormat("invalid")
+	require.Error(t, err)
+}
+
+func resetDefaults(t *testing.T) {
+	t.Helper()
+
+	reset := func() {
+		defaults.set(func(o *options) {
+			*o = newDefaultOptions().options
+		})
+	}
+
+	reset()
+
+	t.Cleanup(reset)
+}
@@ -0,0 +1,168 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package log
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// maxErrorDepth limits the depth of the causes of an error field.
+const maxErrorDepth = 16
+
+// ErrorCoder is implemented by errors which expose an error code. The code is output
+// by WithErrorDetails.
+type ErrorCoder interface {
+	ErrorCode() string
+}
+
+type errorDetailsOptions struct {
+	stack bool
+}
+
+// ErrorDetailsOption is an option for WithErrorDetails.
+type ErrorDetailsOption func(o *errorDetailsOptions)
+
+// IncludeErrorStack includes the stack trace of the error (see WithErrorStacktrace for the supported errors).
+func IncludeErrorStack() ErrorDetailsOption {
+	return func(o *errorDetailsOptions) {
+		o.stack = true
+	}
+}
+
+// WithErrorDetails sets the error field to an object which describes the error and its causes, which are
+// found by unwrapping the error (including errors created with errors.Join). For example:
+//
+//	"error": {
+//	  "message": "get user: connection refused",
+//	  "type": "*fmt.wrapError",
+//	  "root_cause": "connection refused",
+//	  "causes": [{"message": "connection refused", "type": "*errors.errorString", "code": "E42"}]
+//	}
+//
+// The code is only output for errors that implement ErrorCoder.
+func WithErrorDetails(err error, opts ...ErrorDetailsOption) zap.Field {
+	if err == nil {
+		return zap.Skip()
+	}
+
+	details := &errorDetails{err: err}
+
+	for _, opt := range opts {
+		opt(&details.options)
+	}
+
+	return zap.Object(FieldError, details)
+}
+
+type errorDetails struct {
+	err     error
+	options errorDetailsOptions
+}
+
+func (d *errorDetails) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	if err := marshalError(enc, d.err, 0); err != nil {
+		return err
+	}
+
+	if root, unwrapped := rootCause(d.err); unwrapped {
+		enc.AddString("root_cause", root.Error())
+	}
+
+	if d.options.stack {
+		if pcs := errorStack(d.err); len(pcs) > 0 {
+			enc.AddString("stacktrace", (&stacktraceOptions{skipPackages: defaultStacktraceSkipPackages}).format(pcs))
+		}
+	}
+
+	return nil
+}
+
+func marshalError(enc zapcore.ObjectEncoder, err error, depth int) error {
+	enc.AddString("message", err.Error())
+	enc.AddString("type", fmt.Sprintf("%T", err))
+
+	if coder, ok := err.(ErrorCoder); ok { //nolint:errorlint
+		enc.AddString("code", coder.ErrorCode())
+	}
+
+	causes := unwrapError(err)
+	if len(causes) == 0 || depth >= maxErrorDepth {
+		return nil
+	}
+
+	return enc.AddArray("causes", zapcore.ArrayMarshalerFunc(func(arr zapcore.ArrayEncoder) error {
+		for _, cause := range causes {
+			err := arr.AppendObject(zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+				return marshalError(enc, cause, depth+1)
+			}))
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}))
+}
+
+// unwrapError returns the (non-nil) errors wrapped by the given error.
+func unwrapError(err error) []error {
+	switch e := err.(type) { //nolint:errorlint
+	case interface{ Unwrap() error }:
+		if wrapped := e.Unwrap(); wrapped != nil {
+			return []error{wrapped}
+		}
+	case interface{ Unwrap() []error }:
+		var causes []error
+
+		for _, cause := range e.Unwrap() {
+			if cause != nil {
+				causes = append(causes, cause)
+			}
+		}
+
+		return causes
+	}
+
+	return nil
+}
+
+// rootCause returns the innermost error, following the first error of joined errors, and whether the
+// given error wraps any error. The errors aren't compared since not every error type is comparable.
+func rootCause(err error) (error, bool) {
+	unwrapped := false
+
+	for depth := 0; depth < maxErrorDepth; depth++ {
+		causes := unwrapError(err)
+		if len(causes) == 0 {
+			break
+		}
+
+		err = causes[0]
+		unwrapped = true
+	}
+
+	return err, unwrapped
+}
+
+// errorFromField returns the error of an error field (see WithError and WithErrorDetails).
+func errorFromField(field zapcore.Field) (error, bool) {
+	switch field.Type { //nolint:exhaustive
+	case zapcore.ErrorType:
+		err, ok := field.Interface.(error)
+
+		return err, ok
+	case zapcore.ObjectMarshalerType:
+		if details, ok := field.Interface.(*errorDetails); ok {
+			return details.err, true
+		}
+	}
+
+	return nil, false
+}
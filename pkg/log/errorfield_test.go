@@ -0,0 +1,184 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package log
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type codedError struct {
+	code string
+}
+
+func (e *codedError) Error() string {
+	return "connection refused"
+}
+
+func (e *codedError) ErrorCode() string {
+	return e.code
+}
+
+// multiError is a (value) error type which can't be compared.
+type multiError struct {
+	errs []error
+}
+
+func (e multiError) Error() string {
+	var messages []string
+
+	for _, err := range e.errs {
+		if err != nil {
+			messages = append(messages, err.Error())
+		}
+	}
+
+	return strings.Join(messages, "\n")
+}
+
+func (e multiError) Unwrap() []error {
+	return e.errs
+}
+
+func TestWithErrorDetails(t *testing.T) {
+	const module = "error-details-module"
+
+	t.Run("chain", func(t *testing.T) {
+		err := fmt.Errorf("get user: %w", errors.Join(&codedError{code: "E42"}, errors.New("timeout")))
+
+		entries := logEntries(t, module, JSON, func(l *Log) {
+			l.Error("Sample error log", WithErrorDetails(err))
+		})
+
+		require.Equal(t, map[string]interface{}{
+			"message":    "get user: connection refused\ntimeout",
+			"type":       "*fmt.wrapError",
+			"root_cause": "connection refused",
+			"causes": []interface{}{
+				map[string]interface{}{
+					"message": "connection refused\ntimeout",
+					"type":    "*errors.joinError",
+					"causes": []interface{}{
+						map[string]interface{}{
+							"message": "connection refused",
+							"type":    "*log.codedError",
+							"code":    "E42",
+						},
+						map[string]interface{}{
+							"message": "timeout",
+							"type":    "*errors.errorString",
+						},
+					},
+				},
+			},
+		}, entries[0][FieldError])
+	})
+
+	t.Run("uncomparable error", func(t *testing.T) {
+		entries := logEntries(t, module, JSON, func(l *Log) {
+			l.Error("Sample error log", WithErrorDetails(multiError{errs: []error{nil, errors.New("timeout")}}))
+			l.Error("Sample error log", WithErrorDetails(multiError{}))
+		})
+
+		require.Equal(t, map[string]interface{}{
+			"message":    "timeout",
+			"type":       "log.multiError",
+			"root_cause": "timeout",
+			"causes": []interface{}{
+				map[string]interface{}{
+					"message": "timeout",
+					"type":    "*errors.errorString",
+				},
+			},
+		}, entries[0][FieldError])
+
+		require.Equal(t, map[string]interface{}{
+			"message": "",
+			"type":    "log.multiError",
+		}, entries[1][FieldError])
+	})
+
+	t.Run("no causes", func(t *testing.T) {
+		entries := logEntries(t, module, JSON, func(l *Log) {
+			l.Error("Sample error log", WithErrorDetails(errors.New("failed")))
+			l.Error("Sample error log", WithErrorDetails(nil))
+		})
+
+		require.Equal(t, map[string]interface{}{
+			"message": "failed",
+			"type":    "*errors.errorString",
+		}, entries[0][FieldError])
+
+		require.NotContains(t, entries[1], FieldError)
+	})
+
+	t.Run("stack", func(t *testing.T) {
+		enc := zapcore.NewMapObjectEncoder()
+
+		WithErrorDetails(fmt.Errorf("wrapped: %w", newStackError("origin")), IncludeErrorStack()).AddTo(enc)
+
+		details, ok := enc.Fields[FieldError].(map[string]interface{})
+		require.True(t, ok)
+
+		stack, ok := details["stacktrace"].(string)
+		require.True(t, ok)
+		require.True(t, strings.HasPrefix(stack, "testing.tRunner\n"), stack)
+
+		enc = zapcore.NewMapObjectEncoder()
+
+		WithErrorDetails(errors.New("no stack"), IncludeErrorStack()).AddTo(enc)
+
+		require.NotContains(t, enc.Fields[FieldError], "stacktrace")
+	})
+
+	t.Run("depth", func(t *testing.T) {
+		err := errors.New("root")
+
+		for i := 0; i < maxErrorDepth+5; i++ {
+			err = fmt.Errorf("wrap %d: %w", i, err)
+		}
+
+		enc := zapcore.NewMapObjectEncoder()
+
+		WithErrorDetails(err).AddTo(enc)
+
+		depth := 0
+
+		for details := enc.Fields[FieldError].(map[string]interface{}); details["causes"] != nil; depth++ {
+			details = details["causes"].([]interface{})[0].(map[string]interface{})
+		}
+
+		require.Equal(t, maxErrorDepth, depth)
+	})
+
+	t.Run("span event", func(t *testing.T) {
+		recorder := tracetest.NewSpanRecorder()
+
+		ctx, span := trace.NewTracerProvider(trace.WithSpanProcessor(recorder)).
+			Tracer("unit-test").Start(context.Background(), "span")
+
+		SetLevel(module, DEBUG)
+
+		New(module, WithSpanEvents(), WithRoute(newMockWriter(), DEBUG, FATAL)).
+			Errorc(ctx, "Sample error log", WithErrorDetails(errors.New("failed")), zap.String("key", "value"))
+
+		span.End()
+
+		events := recorder.Ended()[0].Events()
+		require.Len(t, events, 2)
+		require.Equal(t, "exception", events[0].Name)
+	})
+}
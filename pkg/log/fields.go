@@ -21,6 +21,7 @@ import (
 const (
 	FieldAddress       = "address"
 	FieldDuration      = "duration"
+	FieldError         = "error"
 	FieldHTTPStatus    = "httpStatus"
 	FieldID            = "id"
 	FieldName          = "name"
@@ -116,45 +117,56 @@ func WithCorrelationID(value string) zap.Field {
 // otelMarshaller is an OpenTelemetry marshaller which adds Open-Telemetry
 // trace and span IDs (as well as parent span ID if exists) to the log message.
 type otelMarshaller struct {
-	ctx context.Context
+	ctx    context.Context
+	format *tracingFormat
 }
 
 type childSpan interface {
 	Parent() trace.SpanContext
 }
 
-const (
-	nilTraceID = "00000000000000000000000000000000"
-	nilSpanID  = "0000000000000000"
-)
-
 func (m *otelMarshaller) MarshalLogObject(e zapcore.ObjectEncoder) error {
+	f := m.format
+	if f == nil {
+		f = defaultTracingFormat
+	}
+
 	s := trace.SpanFromContext(m.ctx)
 
-	traceID := s.SpanContext().TraceID().String()
-	if traceID == "" || traceID == nilTraceID {
+	sc := s.SpanContext()
+	if !sc.TraceID().IsValid() {
 		return nil
 	}
 
-	e.AddString(FieldTraceID, traceID)
+	addString(e, f.traceIDKey, f.formatTraceID(sc))
 
-	spanID := s.SpanContext().SpanID().String()
-	if spanID != "" && spanID != nilSpanID {
-		e.AddString(FieldSpanID, spanID)
+	if sc.SpanID().IsValid() {
+		addString(e, f.spanIDKey, f.formatSpanID(sc.SpanID()))
+	}
+
+	if f.sampledKey != "" {
+		e.AddBool(f.sampledKey, sc.IsSampled())
 	}
 
 	cspan, ok := s.(childSpan)
 	if ok {
-		parentSpanID := cspan.Parent().SpanID().String()
-		if parentSpanID != "" && parentSpanID != nilSpanID {
-			e.AddString(FieldParentSpanID, parentSpanID)
+		parentSpanID := cspan.Parent().SpanID()
+		if parentSpanID.IsValid() {
+			addString(e, f.parentSpanIDKey, f.formatSpanID(parentSpanID))
 		}
 	}
 
 	member := baggage.FromContext(m.ctx).Member(api.CorrelationIDHeader)
 	if member.Value() != "" {
-		e.AddString(FieldCorrelationID, member.Value())
+		addString(e, f.correlationIDKey, member.Value())
 	}
 
 	return nil
 }
+
+// addString adds the given string to the encoder unless the key is omitted.
+func addString(e zapcore.ObjectEncoder, key, value string) {
+	if key != zapcore.OmitKey {
+		e.AddString(key, value)
+	}
+}
@@ -0,0 +1,236 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package log
+
+import (
+	"container/list"
+	"sync"
+
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/trustbloc/logutil-go/pkg/otel/api"
+)
+
+// flightRecorderMaxKeys is the maximum number of traces (or correlation IDs) for which entries are
+// buffered by a logger. If exceeded, the entries of the least recently started trace are discarded.
+const flightRecorderMaxKeys = 1000
+
+// WithFlightRecorder enables the flight recorder. Entries below the enabled level of the module are not
+// discarded but are kept in an in-memory ring buffer which holds up to the given number of entries per trace
+// (or per correlation ID, or per logger for entries without a trace). The named children of the logger (see Named)
+// share its flight recorder, so an error flushes the buffered entries of the same trace of all of them. When an ERROR (or above) is logged then
+// the buffered entries of the same trace are written before the error, which provides the debug context
+// of a failure while only logging at INFO level (for example) otherwise.
+//
+// Note that the fields of the buffered entries are encoded when the entries are flushed, so values which
+// are modified after they were logged (for example, byte slices) are written as modified. Since entries below
+// the level are buffered, the logger doesn't skip them early, which makes disabled logs more expensive.
+//
+// With WithMetrics, the buffered entries are counted as suppressed, and as written when they are flushed.
+// The hooks (see WithHooks) are called for the buffered entries when they are flushed.
+func WithFlightRecorder(size int) Option {
+	return func(o *options) {
+		o.flightRecorderSize = size
+	}
+}
+
+// withFlightRecorderOf is an internal option which shares the flight recorder of a logger with its named children.
+func withFlightRecorderOf(recorder *flightRecorder) Option {
+	return func(o *options) {
+		o.flightRecorder = recorder
+	}
+}
+
+// flightRecorderCore wraps the core of a logger. Entries that are enabled are passed on to the wrapped
+// core. Entries that are not enabled are buffered in the flight recorder. For ERROR entries (and above),
+// a flightRecorderFlusher is added to the checked entry which writes the buffered entries of the same
+// trace to the flush cores of the loggers which buffered them. The flush cores apply the levels of the routes
+// but not the level of the module.
+type flightRecorderCore struct {
+	zapcore.Core
+	flushCore zapcore.Core
+	recorder  *flightRecorder
+	context   []zapcore.Field
+}
+
+func newFlightRecorderCore(core, flushCore zapcore.Core, recorder *flightRecorder) zapcore.Core {
+	return &flightRecorderCore{
+		Core:      core,
+		flushCore: flushCore,
+		recorder:  recorder,
+	}
+}
+
+func (c *flightRecorderCore) Enabled(zapcore.Level) bool {
+	return true
+}
+
+func (c *flightRecorderCore) With(fields []zapcore.Field) zapcore.Core {
+	return &flightRecorderCore{
+		Core:      c.Core.With(fields),
+		flushCore: c.flushCore,
+		recorder:  c.recorder,
+		context:   append(c.context[:len(c.context):len(c.context)], fields...),
+	}
+}
+
+// buffers returns true if the entries of the given level are buffered rather than written.
+func (c *flightRecorderCore) buffers(level zapcore.Level) bool {
+	return !c.Core.Enabled(level)
+}
+
+func (c *flightRecorderCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.buffers(entry.Level) {
+		return ce.AddCore(entry, c)
+	}
+
+	if entry.Level >= zapcore.ErrorLevel {
+		ce = ce.AddCore(entry, &flightRecorderFlusher{flightRecorderCore: c})
+	}
+
+	return c.Core.Check(entry, ce)
+}
+
+// Write buffers the entry.
+func (c *flightRecorderCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	c.recorder.record(flightRecorderKey(fields, c.context), &recordedEntry{
+		entry:     entry,
+		fields:    append([]zapcore.Field(nil), fields...),
+		flushCore: c.flushCore,
+		context:   c.context,
+	})
+
+	return nil
+}
+
+// flightRecorderFlusher writes the buffered entries of the trace of the entry which is written.
+type flightRecorderFlusher struct {
+	*flightRecorderCore
+}
+
+func (f *flightRecorderFlusher) Write(_ zapcore.Entry, fields []zapcore.Field) error {
+	for _, e := range f.recorder.take(flightRecorderKey(fields, f.context)) {
+		// The entry is written with the module and fields of the logger which buffered it.
+		core := e.flushCore
+		if len(e.context) > 0 {
+			core = core.With(e.context)
+		}
+
+		if ce := core.Check(e.entry, nil); ce != nil {
+			writeChecked(ce, e.entry, e.fields)
+		}
+	}
+
+	return nil
+}
+
+// flightRecorderKey returns the trace ID (or correlation ID) of the given fields, or an empty string.
+func flightRecorderKey(fieldSets ...[]zapcore.Field) string {
+	for _, fields := range fieldSets {
+		if ctx := contextFromFields(fields); ctx != nil {
+			if sc := trace.SpanContextFromContext(ctx); sc.TraceID().IsValid() {
+				return sc.TraceID().String()
+			}
+
+			if member := baggage.FromContext(ctx).Member(api.CorrelationIDHeader); member.Value() != "" {
+				return member.Value()
+			}
+		}
+
+		for _, field := range fields {
+			if field.Key == FieldCorrelationID && field.Type == zapcore.StringType {
+				return field.String
+			}
+		}
+	}
+
+	return ""
+}
+
+type recordedEntry struct {
+	entry     zapcore.Entry
+	fields    []zapcore.Field
+	flushCore zapcore.Core
+	context   []zapcore.Field
+}
+
+// flightRecorder holds a ring buffer of entries per key. If the maximum number of keys is exceeded then
+// the buffer of the oldest key is discarded.
+type flightRecorder struct {
+	size    int
+	maxKeys int
+	buffers map[string]*list.Element
+	keys    *list.List
+	mutex   sync.Mutex
+}
+
+type ringBuffer struct {
+	key     string
+	entries []*recordedEntry
+	next    int
+	full    bool
+}
+
+func newFlightRecorder(size, maxKeys int) *flightRecorder {
+	return &flightRecorder{
+		size:    size,
+		maxKeys: maxKeys,
+		buffers: make(map[string]*list.Element),
+		keys:    list.New(),
+	}
+}
+
+func (r *flightRecorder) record(key string, entry *recordedEntry) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	element, ok := r.buffers[key]
+	if !ok {
+		if r.keys.Len() >= r.maxKeys {
+			oldest := r.keys.Front()
+
+			delete(r.buffers, oldest.Value.(*ringBuffer).key) //nolint:forcetypeassert
+			r.keys.Remove(oldest)
+		}
+
+		element = r.keys.PushBack(&ringBuffer{key: key, entries: make([]*recordedEntry, r.size)})
+		r.buffers[key] = element
+	}
+
+	buffer := element.Value.(*ringBuffer) //nolint:forcetypeassert
+
+	buffer.entries[buffer.next] = entry
+	buffer.next = (buffer.next + 1) % r.size
+
+	if buffer.next == 0 {
+		buffer.full = true
+	}
+}
+
+// take removes and returns the buffered entries of the given key, oldest first.
+func (r *flightRecorder) take(key string) []*recordedEntry {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	element, ok := r.buffers[key]
+	if !ok {
+		return nil
+	}
+
+	delete(r.buffers, key)
+	r.keys.Remove(element)
+
+	buffer := element.Value.(*ringBuffer) //nolint:forcetypeassert
+
+	if !buffer.full {
+		return buffer.entries[:buffer.next]
+	}
+
+	return append(buffer.entries[buffer.next:], buffer.entries[:buffer.next]...)
+}
@@ -0,0 +1,181 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package log
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.uber.org/zap"
+)
+
+func TestFlightRecorder(t *testing.T) {
+	const module = "flight-recorder-module"
+
+	SetLevel(module, INFO)
+
+	tracer := trace.NewTracerProvider().Tracer("unit-test")
+
+	t.Run("flush on error", func(t *testing.T) {
+		output := newMockWriter()
+
+		logger := New(module, WithRoute(output, DEBUG, FATAL), WithFlightRecorder(2)).With(zap.String("key", "value"))
+
+		ctx1, span1 := tracer.Start(context.Background(), "span1")
+		defer span1.End()
+
+		ctx2, span2 := tracer.Start(context.Background(), "span2")
+		defer span2.End()
+
+		logger.Debugc(ctx1, "Debug log 1 of trace 1")
+		logger.Debugc(ctx2, "Debug log of trace 2")
+		logger.Debugc(ctx1, "Debug log 2 of trace 1")
+		logger.Debugc(ctx1, "Debug log 3 of trace 1")
+		logger.Infoc(ctx1, "Info log of trace 1")
+
+		require.NotContains(t, output.String(), "Debug log")
+		require.Contains(t, output.String(), "Info log of trace 1")
+
+		logger.Errorc(ctx1, "Error log of trace 1")
+
+		lines := strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n")
+		require.Len(t, lines, 4)
+
+		// Only the last two debug entries of trace 1 are written, before the error.
+		require.Contains(t, lines[0], "Info log of trace 1")
+		require.Contains(t, lines[1], `"msg":"Debug log 2 of trace 1","key":"value","trace_id":"`+
+			span1.SpanContext().TraceID().String())
+		require.Contains(t, lines[1], `"caller":"log/flightrecorder_test.go:`)
+		require.Contains(t, lines[2], "Debug log 3 of trace 1")
+		require.Contains(t, lines[3], "Error log of trace 1")
+
+		// The entries are flushed only once.
+		output.Reset()
+		logger.Errorc(ctx1, "Error log of trace 1")
+		require.NotContains(t, output.String(), "Debug log")
+
+		logger.Errorc(ctx2, "Error log of trace 2")
+		require.Contains(t, output.String(), "Debug log of trace 2")
+	})
+
+	t.Run("without trace", func(t *testing.T) {
+		output := newMockWriter()
+
+		logger := New(module, WithRoute(output, DEBUG, FATAL), WithFlightRecorder(10))
+
+		logger.Debug("Debug log")
+		logger.Debug("Debug log with correlation ID", WithCorrelationID("abc"))
+		logger.Error("Error log")
+
+		require.Contains(t, output.String(), "Debug log")
+		require.NotContains(t, output.String(), "Debug log with correlation ID")
+
+		logger.Error("Error log with correlation ID", WithCorrelationID("abc"))
+
+		require.Contains(t, output.String(), "Debug log with correlation ID")
+	})
+
+	t.Run("hooks", func(t *testing.T) {
+		output := newMockWriter()
+
+		var messages []string
+
+		logger := New(module, WithRoute(output, DEBUG, FATAL), WithFlightRecorder(10),
+			WithHooks(func(entry *Entry) bool {
+				messages = append(messages, entry.Message)
+				entry.Fields = append(entry.Fields, zap.String("tenant", "tenant1"))
+
+				return entry.Message != "Dropped debug log"
+			})).With(zap.String("key", "value"))
+
+		logger.Debug("Debug log")
+		logger.Debug("Dropped debug log")
+
+		// The hooks aren't called for the entries which are buffered.
+		require.Empty(t, messages)
+
+		logger.Error("Error log")
+
+		// The hooks are called for the entries when they are flushed.
+		require.Equal(t, []string{"Error log", "Debug log", "Dropped debug log"}, messages)
+		require.Contains(t, output.String(), `"msg":"Debug log","key":"value","tenant":"tenant1"`)
+		require.NotContains(t, output.String(), "Dropped debug log")
+	})
+
+	t.Run("named children", func(t *testing.T) {
+		output := newMockWriter()
+
+		logger := New(module, WithRoute(output, DEBUG, FATAL), WithFlightRecorder(10))
+		child := logger.Named("child")
+
+		ctx, span := tracer.Start(context.Background(), "span")
+		defer span.End()
+
+		child.Debugc(ctx, "Debug log of child")
+		logger.Errorc(ctx, "Error log")
+
+		// The child shares the flight recorder of the parent, and its entry is written with its module.
+		require.Contains(t, output.String(), `"logger":"`+module+`.child"`)
+		require.Contains(t, output.String(), "Debug log of child")
+	})
+
+	t.Run("route levels apply", func(t *testing.T) {
+		stdOut := newMockWriter()
+		stdErr := newMockWriter()
+
+		logger := New(module, WithStdOut(stdOut), WithStdErr(stdErr), WithFlightRecorder(10))
+
+		logger.Debug("Debug log")
+		logger.Error("Error log")
+
+		require.Contains(t, stdOut.String(), "Debug log")
+		require.NotContains(t, stdErr.String(), "Debug log")
+		require.Contains(t, stdErr.String(), "Error log")
+	})
+}
+
+func TestFlightRecorderBuffer(t *testing.T) {
+	recorder := newFlightRecorder(3, 2)
+
+	entry := func(i int) *recordedEntry {
+		e := &recordedEntry{}
+		e.entry.Message = strconv.Itoa(i)
+
+		return e
+	}
+
+	messages := func(entries []*recordedEntry) []string {
+		var m []string
+
+		for _, e := range entries {
+			m = append(m, e.entry.Message)
+		}
+
+		return m
+	}
+
+	for i := 1; i <= 5; i++ {
+		recorder.record("key1", entry(i))
+	}
+
+	recorder.record("key2", entry(1))
+
+	require.Equal(t, []string{"3", "4", "5"}, messages(recorder.take("key1")))
+	require.Empty(t, recorder.take("key1"))
+
+	// The oldest key is discarded.
+	recorder.record("key3", entry(1))
+	recorder.record("key4", entry(1))
+
+	require.Empty(t, recorder.take("key2"))
+	require.Equal(t, []string{"1"}, messages(recorder.take("key3")))
+	require.Equal(t, []string{"1"}, messages(recorder.take("key4")))
+}
@@ -0,0 +1,167 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package log
+
+import (
+	"context"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Entry is a log entry which is passed to the hooks (see WithHooks).
+type Entry struct {
+	Level   Level
+	Module  string
+	Message string
+	// Fields are the fields of the log call.
+	Fields []zap.Field
+	// LoggerFields are the fields of the logger (see With and WithFields). They are read-only since they are
+	// encoded when they are added to the logger; changes to LoggerFields are ignored.
+	LoggerFields []zap.Field
+	// Context is the context of a context log (Infoc, etc.) or nil for other logs.
+	Context context.Context
+}
+
+// Hook is called for each entry before it is encoded. The hook may change the level, message, fields and
+// context of the entry, and returns false if the entry should be dropped.
+type Hook func(entry *Entry) bool
+
+// WithHooks adds hooks which are called, in the given order, for each entry that is enabled by the log level.
+// The entries which are buffered by the flight recorder (see WithFlightRecorder) are passed to the hooks when
+// they are flushed. The default hooks are called before the hooks given to New. Configure replaces the default hooks with the
+// hooks given to it (if any), so Configure(WithHooks()) removes them. Example:
+//
+//	err := log.Configure(log.WithHooks(func(entry *log.Entry) bool {
+//		if tenant, ok := tenantFromContext(entry.Context); ok {
+//			entry.Fields = append(entry.Fields, zap.String("tenant", tenant))
+//		}
+//
+//		return entry.Message != "Health check"
+//	}))
+//
+// If a hook changes the level of an entry then the entry is routed according to the new level, and it is
+// dropped if the new level is disabled. The logger panics (or exits) at PANIC (or FATAL) level regardless
+// of the level set by a hook.
+func WithHooks(hooks ...Hook) Option {
+	return func(o *options) {
+		o.hooks = append(o.hooks[:len(o.hooks):len(o.hooks)], hooks...)
+		o.hooksSet = true
+	}
+}
+
+type generationHooks struct {
+	generation uint64
+	hooks      []Hook
+}
+
+// hookProvider resolves the hooks of a logger from the options given to New and the current defaults.
+// The hooks are resolved again when the defaults change.
+type hookProvider struct {
+	opts    []Option
+	current atomic.Pointer[generationHooks]
+}
+
+func (p *hookProvider) get() []Hook {
+	generation := defaults.generation.Load()
+
+	if c := p.current.Load(); c != nil && c.generation == generation {
+		return c.hooks
+	}
+
+	hooks := getOptions(p.opts).hooks
+
+	p.current.Store(&generationHooks{generation: generation, hooks: hooks})
+
+	return hooks
+}
+
+// hookCore is the outermost core of a logger. It passes the entries to the hooks and checks the resulting
+// entries against the wrapped core, so that the wrapped cores (for example, the metrics core) only see the
+// entries that aren't dropped.
+type hookCore struct {
+	zapcore.Core
+	module string
+	hooks  *hookProvider
+	fields []zapcore.Field
+}
+
+func newHookCore(core zapcore.Core, module string, hooks *hookProvider) zapcore.Core {
+	return &hookCore{Core: core, module: module, hooks: hooks}
+}
+
+func (c *hookCore) With(fields []zapcore.Field) zapcore.Core {
+	return &hookCore{
+		Core:   c.Core.With(fields),
+		module: c.module,
+		hooks:  c.hooks,
+		fields: append(c.fields[:len(c.fields):len(c.fields)], fields...),
+	}
+}
+
+func (c *hookCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if len(c.hooks.get()) == 0 {
+		return c.Core.Check(entry, ce)
+	}
+
+	// The flight recorder is enabled for all levels. The entries which it buffers (since their level isn't
+	// enabled) are passed to the hooks when they are flushed.
+	if recorder, ok := c.Core.(*flightRecorderCore); ok {
+		if recorder.buffers(entry.Level) {
+			return ce.AddCore(entry, recorder)
+		}
+
+		return ce.AddCore(entry, c)
+	}
+
+	if c.Core.Enabled(entry.Level) {
+		return ce.AddCore(entry, c)
+	}
+
+	return ce
+}
+
+func (c *hookCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	e := &Entry{
+		Level:   Level(entry.Level),
+		Module:  c.module,
+		Message: entry.Message,
+		Fields:  make([]zap.Field, 0, len(fields)),
+		Context: contextFromFields(fields),
+		// A copy is passed so that a hook can't modify the fields of the logger.
+		LoggerFields: append([]zap.Field(nil), c.fields...),
+	}
+
+	// The tracing field is replaced by the context of the entry.
+	for _, field := range fields {
+		if !isTracingField(field) {
+			e.Fields = append(e.Fields, field)
+		}
+	}
+
+	for _, hook := range c.hooks.get() {
+		if !hook(e) {
+			return nil
+		}
+	}
+
+	entry.Level = zapcore.Level(e.Level)
+	entry.Message = e.Message
+
+	fields = e.Fields
+	if e.Context != nil {
+		fields = append(fields, WithTracing(e.Context))
+	}
+
+	// The entry is checked again since the hooks may have changed its level.
+	if ce := c.Core.Check(entry, nil); ce != nil {
+		writeChecked(ce, entry, fields)
+	}
+
+	return nil
+}
@@ -0,0 +1,215 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package log
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/zap"
+)
+
+type tenantKey struct{}
+
+type failingWriter struct{}
+
+func (w *failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("collector unavailable")
+}
+
+func (w *failingWriter) Sync() error {
+	return nil
+}
+
+// captureNestedErrorOutput replaces the error output of the nested checked entries for the duration of the test.
+func captureNestedErrorOutput(t *testing.T) *mockWriter {
+	t.Helper()
+
+	errorOutput := newMockWriter()
+
+	prev := nestedErrorOutput
+	nestedErrorOutput = errorOutput
+
+	t.Cleanup(func() { nestedErrorOutput = prev })
+
+	return errorOutput
+}
+
+func TestHooks(t *testing.T) {
+	const module = "hook-module"
+
+	ctx, span := trace.NewTracerProvider().Tracer("unit-test").Start(
+		context.WithValue(context.Background(), tenantKey{}, "tenant1"), "span")
+	defer span.End()
+
+	addTenant := func(entry *Entry) bool {
+		require.Equal(t, []zap.Field{zap.String("app", "vcs"), zap.String("key", "value")}, entry.LoggerFields)
+
+		if entry.Context != nil {
+			entry.Fields = append(entry.Fields, zap.Any("tenant", entry.Context.Value(tenantKey{})))
+		}
+
+		return true
+	}
+
+	dropHealthChecks := func(entry *Entry) bool {
+		return entry.Message != "Health check"
+	}
+
+	raiseSeverity := func(entry *Entry) bool {
+		require.Equal(t, module, entry.Module)
+
+		if entry.Message == "Certificate expired" {
+			entry.Level = ERROR
+			entry.Message = "Certificate has expired"
+		}
+
+		return true
+	}
+
+	stdOut := newMockWriter()
+	stdErr := newMockWriter()
+
+	SetLevel(module, INFO)
+
+	logger := New(module, WithStdOut(stdOut), WithStdErr(stdErr),
+		WithHooks(addTenant, dropHealthChecks), WithHooks(raiseSeverity),
+		WithFields(zap.String("app", "vcs"))).With(zap.String("key", "value"))
+
+	logger.Infoc(ctx, "Sample info log", zap.Int("count", 3))
+	logger.Info("Health check")
+	logger.Warn("Certificate expired")
+
+	require.Contains(t, stdOut.String(), `"msg":"Sample info log"`)
+	require.Contains(t, stdOut.String(), `"app":"vcs","key":"value","count":3,"tenant":"tenant1","trace_id":"`)
+	require.Contains(t, stdOut.String(), `"caller":"log/hook_test.go:`)
+	require.NotContains(t, stdOut.String(), "Health check")
+	require.NotContains(t, stdOut.String(), "Certificate")
+
+	require.Contains(t, stdErr.String(), `"level":"error"`)
+	require.Contains(t, stdErr.String(), `"msg":"Certificate has expired"`)
+
+	t.Run("configure", func(t *testing.T) {
+		resetDefaults(t)
+
+		var modules []string
+
+		require.NoError(t, Configure(WithHooks(func(entry *Entry) bool {
+			modules = append(modules, entry.Module)
+
+			return entry.Level >= WARNING
+		})))
+
+		stdOut.Reset()
+
+		logger.Info("Health check")
+		logger.Debug("Sample debug log")
+
+		New(module, WithStdOut(stdOut)).Warn("Sample warn log")
+
+		// The global hooks are applied to both existing and new loggers.
+		require.Equal(t, []string{module, module}, modules)
+		require.NotContains(t, stdOut.String(), "Health check")
+		require.Contains(t, stdOut.String(), "Sample warn log")
+	})
+
+	t.Run("configure replaces hooks", func(t *testing.T) {
+		resetDefaults(t)
+
+		calls := 0
+
+		count := func(*Entry) bool {
+			calls++
+
+			return true
+		}
+
+		require.NoError(t, Configure(WithHooks(count)))
+		require.NoError(t, Configure(WithHooks(count)))
+		require.NoError(t, Configure(WithEncoding(Console)))
+
+		logger.Warn("Sample warn log")
+		require.Equal(t, 1, calls)
+
+		require.NoError(t, Configure(WithHooks()))
+
+		logger.Warn("Sample warn log")
+		require.Equal(t, 1, calls)
+	})
+
+	t.Run("apply config keeps hooks", func(t *testing.T) {
+		resetDefaults(t)
+
+		calls := 0
+
+		require.NoError(t, Configure(WithHooks(func(*Entry) bool {
+			calls++
+
+			return true
+		})))
+
+		// A Config can't express hooks, so reloading the configuration doesn't remove them.
+		require.NoError(t, ApplyConfig(&Config{Encoding: Console}))
+
+		logger.Warn("Sample warn log")
+		require.Equal(t, 1, calls)
+	})
+}
+
+func TestHooksDroppedEntry(t *testing.T) {
+	const module = "hook-dropped-module"
+
+	SetLevel(module, INFO)
+
+	reader := sdkmetric.NewManualReader()
+	recorder := tracetest.NewSpanRecorder()
+
+	ctx, span := trace.NewTracerProvider(trace.WithSpanProcessor(recorder)).Tracer("unit-test").
+		Start(context.Background(), "span")
+
+	stdOut := newMockWriter()
+	stdErr := newMockWriter()
+
+	logger := New(module, WithStdOut(stdOut), WithStdErr(stdErr), WithSpanEvents(), WithFlightRecorder(10),
+		WithMetrics(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+		WithHooks(func(entry *Entry) bool {
+			return entry.Message != "Dropped"
+		}))
+
+	logger.Debugc(ctx, "Sample debug log")
+	logger.Infoc(ctx, "Dropped")
+	logger.Errorc(ctx, "Dropped")
+
+	span.End()
+
+	require.Empty(t, stdOut.String())
+	require.Empty(t, stdErr.String())
+	require.Empty(t, recorder.Ended()[0].Events())
+
+	rm := &metricdata.ResourceMetrics{}
+	require.NoError(t, reader.Collect(context.Background(), rm))
+
+	require.Empty(t, counterValues(t, rm, metricEntries))
+	require.Equal(t, map[string]int64{DEBUG.String(): 1}, counterValues(t, rm, metricEntriesSuppressed))
+}
+
+func TestHooksWriteError(t *testing.T) {
+	errorOutput := captureNestedErrorOutput(t)
+
+	logger := New("hook-error-module", WithStdOut(&failingWriter{}),
+		WithHooks(func(*Entry) bool { return true }))
+
+	logger.Info("Sample info log")
+
+	require.Contains(t, errorOutput.String(), "write error: collector unavailable")
+}
@@ -0,0 +1,83 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package log
+
+// LevelChangeListener is notified when the effective log level of a module changes.
+type LevelChangeListener func(module string, oldLevel, newLevel Level)
+
+type levelListener struct {
+	module   string
+	listener LevelChangeListener
+}
+
+// OnLevelChange registers a listener which is notified when SetLevel, SetDefaultLevel or SetSpec change the
+// effective log level of the given module (for example, when the default level is changed and no level
+// is set for the module). The listener is invoked synchronously by the function that changed the level.
+// The returned function unregisters the listener.
+//
+// Example (enable payload capture while the module is at DEBUG level):
+//
+//	unsubscribe := log.OnLevelChange("module1", func(_ string, _, level log.Level) {
+//		capture.SetEnabled(level <= log.DEBUG)
+//	})
+//	defer unsubscribe()
+func OnLevelChange(module string, listener LevelChangeListener) (unsubscribe func()) {
+	return levels.OnLevelChange(module, listener)
+}
+
+// OnLevelChange registers a listener which is notified when the effective log level of the given module
+// changes in the registry (see the package-level OnLevelChange).
+func (l *LevelRegistry) OnLevelChange(module string, listener LevelChangeListener) (unsubscribe func()) {
+	l.rwmutex.Lock()
+	defer l.rwmutex.Unlock()
+
+	l.nextID++
+
+	id := l.nextID
+
+	l.listeners[id] = &levelListener{module: module, listener: listener}
+
+	return func() {
+		l.rwmutex.Lock()
+		delete(l.listeners, id)
+		l.rwmutex.Unlock()
+	}
+}
+
+// update applies the given changes to the levels (while holding the lock) and notifies the listeners of the modules whose
+// effective level has changed. The listeners are invoked after the lock is released so that they
+// may safely call functions such as GetLevel.
+func (l *LevelRegistry) update(apply func()) {
+	type notification struct {
+		listener           *levelListener
+		oldLevel, newLevel Level
+	}
+
+	l.rwmutex.Lock()
+
+	oldLevels := make(map[*levelListener]Level, len(l.listeners))
+
+	for _, ll := range l.listeners {
+		oldLevels[ll] = l.get(ll.module)
+	}
+
+	apply()
+
+	var notifications []notification
+
+	for ll, oldLevel := range oldLevels {
+		if newLevel := l.get(ll.module); newLevel != oldLevel {
+			notifications = append(notifications, notification{listener: ll, oldLevel: oldLevel, newLevel: newLevel})
+		}
+	}
+
+	l.rwmutex.Unlock()
+
+	for _, n := range notifications {
+		n.listener.listener(n.listener.module, n.oldLevel, n.newLevel)
+	}
+}
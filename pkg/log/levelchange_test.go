@@ -0,0 +1,100 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package log
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOnLevelChange(t *testing.T) {
+	const (
+		module1 = "levelchange-module1"
+		module2 = "levelchange-module2"
+	)
+
+	defaultLevel := GetLevel("")
+	defer SetDefaultLevel(defaultLevel)
+
+	SetDefaultLevel(INFO)
+	SetLevel(module1, INFO)
+	SetLevel(module2, INFO)
+
+	type change struct {
+		module             string
+		oldLevel, newLevel Level
+	}
+
+	var changes []change
+
+	listener := func(module string, oldLevel, newLevel Level) {
+		// The new level must already be in effect.
+		require.Equal(t, newLevel, GetLevel(module))
+
+		changes = append(changes, change{module, oldLevel, newLevel})
+	}
+
+	unsubscribe1 := OnLevelChange(module1, listener)
+	unsubscribe2 := OnLevelChange(module2, listener)
+
+	SetLevel(module1, DEBUG)
+	require.Equal(t, []change{{module1, INFO, DEBUG}}, changes)
+
+	// No change in effective level.
+	changes = nil
+	SetLevel(module1, DEBUG)
+	SetLevel(module2, INFO)
+	require.Empty(t, changes)
+
+	// The default level only affects module2 since module1 has its own level.
+	SetLevel(module2+"-other", ERROR)
+	setLevelSpec(t, module1+"=debug")
+	require.Empty(t, changes)
+
+	SetDefaultLevel(WARNING)
+	require.Empty(t, changes, "module2 has its own level")
+
+	// A spec change results in a single notification per module.
+	setLevelSpec(t, module1+"=error:"+module1+"=warning:"+module2+"=debug:info")
+	require.ElementsMatch(t, []change{{module1, DEBUG, WARNING}, {module2, INFO, DEBUG}}, changes)
+
+	unsubscribe1()
+	unsubscribe2()
+
+	changes = nil
+	SetLevel(module1, ERROR)
+	SetLevel(module2, ERROR)
+	require.Empty(t, changes)
+}
+
+func TestOnLevelChangeDefault(t *testing.T) {
+	const module = "levelchange-module3"
+
+	defaultLevel := GetLevel("")
+	defer SetDefaultLevel(defaultLevel)
+
+	SetDefaultLevel(INFO)
+
+	var levels []Level
+
+	unsubscribe := OnLevelChange(module, func(_ string, _, newLevel Level) {
+		levels = append(levels, newLevel)
+	})
+	defer unsubscribe()
+
+	SetDefaultLevel(DEBUG)
+	SetDefaultLevel(ERROR)
+
+	require.Equal(t, []Level{DEBUG, ERROR}, levels)
+}
+
+func setLevelSpec(t *testing.T, spec string) {
+	t.Helper()
+
+	require.NoError(t, SetSpec(spec))
+}
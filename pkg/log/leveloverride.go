@@ -0,0 +1,116 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package log
+
+import (
+	"sort"
+	"time"
+)
+
+// LevelOverride is a temporary log level override of a module.
+type LevelOverride struct {
+	Module string
+	Level  Level
+	Expiry time.Time
+}
+
+type levelOverride struct {
+	level  Level
+	expiry time.Time
+	timer  *time.Timer
+}
+
+// SetLevelFor temporarily sets the log level of the given module. After the given duration, the module
+// returns to its permanent level, including any level set with SetLevel (or SetSpec) while the override
+// was active. Setting an override for a module replaces its active override, if any, and a duration
+// of zero (or less) removes the active override.
+//
+// Example (debug logs for 15 minutes):
+//
+//	log.SetLevelFor("module1", log.DEBUG, 15*time.Minute)
+func SetLevelFor(module string, level Level, ttl time.Duration) {
+	levels.SetLevelFor(module, level, ttl)
+}
+
+// GetLevelOverrides returns the active temporary level overrides, sorted by module.
+func GetLevelOverrides() []LevelOverride {
+	return levels.GetLevelOverrides()
+}
+
+// SetLevelFor temporarily sets the log level of the given module (see the package-level SetLevelFor).
+func (l *LevelRegistry) SetLevelFor(module string, level Level, ttl time.Duration) {
+	l.update(func() {
+		if ttl <= 0 {
+			l.removeOverride(module)
+		} else {
+			l.setOverride(module, level, ttl)
+		}
+	})
+}
+
+// GetLevelOverrides returns the active temporary level overrides, sorted by module.
+func (l *LevelRegistry) GetLevelOverrides() []LevelOverride {
+	l.rwmutex.RLock()
+	defer l.rwmutex.RUnlock()
+
+	overrides := make([]LevelOverride, 0, len(l.overrides))
+
+	for module, o := range l.overrides {
+		overrides = append(overrides, LevelOverride{Module: module, Level: o.level, Expiry: o.expiry})
+	}
+
+	sort.Slice(overrides, func(i, j int) bool {
+		return overrides[i].Module < overrides[j].Module
+	})
+
+	return overrides
+}
+
+// setOverride sets the override for the given module. The lock must be held.
+func (l *LevelRegistry) setOverride(module string, level Level, ttl time.Duration) {
+	l.setOverrideUntil(module, level, time.Now().Add(ttl))
+}
+
+// setOverrideUntil sets the override for the given module, which expires at the given time. The lock must be held.
+func (l *LevelRegistry) setOverrideUntil(module string, level Level, expiry time.Time) {
+	l.removeOverride(module)
+
+	o := &levelOverride{
+		level:  level,
+		expiry: expiry,
+	}
+
+	o.timer = time.AfterFunc(time.Until(expiry), func() {
+		l.update(func() {
+			// The override may have been replaced in the meantime.
+			if l.overrides[module] == o {
+				delete(l.overrides, module)
+			}
+		})
+	})
+
+	l.overrides[module] = o
+}
+
+// removeOverride removes the override of the given module. The lock must be held.
+func (l *LevelRegistry) removeOverride(module string) {
+	if o, ok := l.overrides[module]; ok {
+		o.timer.Stop()
+
+		delete(l.overrides, module)
+	}
+}
+
+// remaining returns the (rounded) duration until the given expiry, which is at least one second.
+func remaining(expiry time.Time) time.Duration {
+	d := time.Until(expiry).Round(time.Second)
+	if d < time.Second {
+		return time.Second
+	}
+
+	return d
+}
@@ -0,0 +1,132 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package log
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetLevelFor(t *testing.T) {
+	const ttl = 100 * time.Millisecond
+
+	t.Run("expires", func(t *testing.T) {
+		const module = "override-module1"
+
+		SetLevel(module, WARNING)
+
+		var (
+			mutex   sync.Mutex
+			changes []Level
+		)
+
+		unsubscribe := OnLevelChange(module, func(_ string, _, newLevel Level) {
+			mutex.Lock()
+			changes = append(changes, newLevel)
+			mutex.Unlock()
+		})
+		defer unsubscribe()
+
+		SetLevelFor(module, DEBUG, ttl)
+
+		require.Equal(t, DEBUG, GetLevel(module))
+
+		overrides := GetLevelOverrides()
+		require.Len(t, overrides, 1)
+		require.Equal(t, module, overrides[0].Module)
+		require.Equal(t, DEBUG, overrides[0].Level)
+		require.WithinDuration(t, time.Now().Add(ttl), overrides[0].Expiry, ttl)
+
+		require.Eventually(t, func() bool {
+			return GetLevel(module) == WARNING
+		}, time.Second, 10*time.Millisecond)
+
+		require.Empty(t, GetLevelOverrides())
+
+		mutex.Lock()
+		require.Equal(t, []Level{DEBUG, WARNING}, changes)
+		mutex.Unlock()
+	})
+
+	t.Run("permanent level set during override", func(t *testing.T) {
+		const module = "override-module2"
+
+		SetLevel(module, WARNING)
+		SetLevelFor(module, DEBUG, ttl)
+		SetLevel(module, ERROR)
+
+		require.Equal(t, DEBUG, GetLevel(module))
+
+		require.Eventually(t, func() bool {
+			return GetLevel(module) == ERROR
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("replace and remove", func(t *testing.T) {
+		const module = "override-module3"
+
+		SetLevel(module, WARNING)
+		SetLevelFor(module, DEBUG, ttl)
+		SetLevelFor(module, INFO, time.Hour)
+
+		require.Equal(t, INFO, GetLevel(module))
+
+		// The replaced override must not remove the new one.
+		time.Sleep(2 * ttl)
+		require.Equal(t, INFO, GetLevel(module))
+
+		SetLevelFor(module, DEBUG, 0)
+		require.Equal(t, WARNING, GetLevel(module))
+		require.Empty(t, GetLevelOverrides())
+	})
+
+	t.Run("default level", func(t *testing.T) {
+		const module = "override-module4"
+
+		SetLevel(module, ERROR)
+
+		defaultLevel := GetLevel("")
+		SetLevelFor("", DEBUG, time.Hour)
+
+		defer SetLevelFor("", DEBUG, 0)
+
+		require.Equal(t, DEBUG, GetLevel("override-no-level"))
+		require.Equal(t, ERROR, GetLevel(module), "module level takes precedence over default override")
+
+		SetLevelFor("", DEBUG, 0)
+		require.Equal(t, defaultLevel, GetLevel("override-no-level"))
+	})
+
+	t.Run("spec", func(t *testing.T) {
+		const module = "override-module5"
+
+		defaultLevel := GetLevel("")
+		defer SetDefaultLevel(defaultLevel)
+
+		require.NoError(t, SetSpec(module+"=warning:"+module+"=debug@1h:info"))
+		defer SetLevelFor(module, DEBUG, 0)
+
+		require.Equal(t, DEBUG, GetLevel(module))
+
+		spec := GetSpec()
+		require.Contains(t, spec, module+"=WARN:")
+		require.Regexp(t, module+`=DEBUG@(1h0m0s|59m59s|59m58s):`, spec)
+		require.True(t, strings.HasSuffix(spec, ":INFO"))
+
+		require.EqualError(t, SetSpec(module+"=debug@abc"), "invalid duration for module "+module+": abc")
+		require.Error(t, SetSpec(module+"=debug@-1m"))
+	})
+}
+
+func TestRemaining(t *testing.T) {
+	require.Equal(t, time.Second, remaining(time.Now()))
+	require.Equal(t, time.Minute, remaining(time.Now().Add(time.Minute+100*time.Millisecond)))
+}
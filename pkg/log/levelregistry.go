@@ -0,0 +1,225 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package log
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LevelRegistry maintains the log levels of modules. Module names are hierarchical: a module without a
+// level of its own (for example, "a.b") inherits the level of its parent ("a"). The package-level functions
+// (SetLevel, SetSpec, etc.) use the global registry, which is used by all loggers unless a registry is given
+// with WithLevelRegistry, for example in order to give the loggers of different tenants different levels.
+type LevelRegistry struct {
+	levels    map[string]Level
+	overrides map[string]*levelOverride
+	listeners map[uint64]*levelListener
+	nextID    uint64
+	rwmutex   sync.RWMutex
+}
+
+// NewLevelRegistry returns a new level registry in which all modules are at the default level (INFO).
+func NewLevelRegistry() *LevelRegistry {
+	return &LevelRegistry{
+		levels:    make(map[string]Level),
+		overrides: make(map[string]*levelOverride),
+		listeners: make(map[uint64]*levelListener),
+	}
+}
+
+// WithLevelRegistry sets the registry of the log levels of the logger. By default, the global registry is used.
+func WithLevelRegistry(registry *LevelRegistry) Option {
+	return func(o *options) {
+		o.levels = registry
+	}
+}
+
+func (o *options) levelRegistry() *LevelRegistry {
+	if o.levels == nil {
+		return levels
+	}
+
+	return o.levels
+}
+
+// GetLevel returns the log level for the given module.
+func (l *LevelRegistry) GetLevel(module string) Level {
+	l.rwmutex.RLock()
+	defer l.rwmutex.RUnlock()
+
+	return l.get(module)
+}
+
+// get returns the effective level of the given module. If no level is set for the module then the level of
+// its closest parent module is used (for example, "a.b" for the module "a.b.c"), and finally the default level.
+// At each step an override takes precedence over the level that is set.
+func (l *LevelRegistry) get(module string) Level {
+	for m := module; ; m = parentModule(m) {
+		if o, ok := l.overrides[m]; ok {
+			return o.level
+		}
+
+		if level, ok := l.levels[m]; ok {
+			return level
+		}
+
+		if m == defaultModuleName {
+			// no configuration exists, default to info
+			return defaultLevel
+		}
+	}
+}
+
+// parentModule returns the parent of the given (dot-separated) module name, which is the default
+// module for a top-level module.
+func parentModule(module string) string {
+	if i := strings.LastIndexByte(module, '.'); i >= 0 {
+		return module[:i]
+	}
+
+	return defaultModuleName
+}
+
+// all returns all set log levels.
+func (l *LevelRegistry) all() map[string]Level {
+	l.rwmutex.RLock()
+	defer l.rwmutex.RUnlock()
+
+	levelsCopy := make(map[string]Level, len(l.levels))
+
+	for module, logLevel := range l.levels {
+		levelsCopy[module] = logLevel
+	}
+
+	return levelsCopy
+}
+
+// SetLevel sets the log level for given module and level. If a temporary level override is active
+// for the module (see SetLevelFor) then the given level takes effect when the override expires.
+func (l *LevelRegistry) SetLevel(module string, level Level) {
+	l.update(func() {
+		l.levels[module] = level
+	})
+}
+
+// SetDefaultLevel sets the default log level.
+func (l *LevelRegistry) SetDefaultLevel(level Level) {
+	l.SetLevel(defaultModuleName, level)
+}
+
+// isEnabled will return true if logging is enabled for given module and level.
+func (l *LevelRegistry) isEnabled(module string, level Level) bool {
+	return level >= l.GetLevel(module)
+}
+
+// SetSpec sets the log levels for individual modules as well as the default log level (see the
+// package-level SetSpec for the format of the spec).
+func (l *LevelRegistry) SetSpec(spec string) error {
+	defaultLogLevel, moduleLevelPairs, err := parseSpec(spec)
+	if err != nil {
+		return err
+	}
+
+	l.setSpec(defaultLogLevel, moduleLevelPairs)
+
+	return nil
+}
+
+func (l *LevelRegistry) setSpec(defaultLogLevel Level, moduleLevelPairs []moduleLevelPair) {
+	// The levels are updated at once so that listeners are notified only of the resulting level changes.
+	l.update(func() {
+		if defaultLogLevel >= minLogLevel {
+			l.levels[defaultModuleName] = defaultLogLevel
+		} else {
+			l.levels[defaultModuleName] = INFO
+		}
+
+		for _, moduleLevelPair := range moduleLevelPairs {
+			if moduleLevelPair.ttl > 0 {
+				l.setOverride(moduleLevelPair.module, moduleLevelPair.logLevel, moduleLevelPair.ttl)
+			} else {
+				l.levels[moduleLevelPair.module] = moduleLevelPair.logLevel
+			}
+		}
+	})
+}
+
+// GetSpec returns the log spec which specifies the log level of each individual module (see the
+// package-level GetSpec for the format of the spec).
+func (l *LevelRegistry) GetSpec() string {
+	var spec string
+
+	var defaultDebugLevel string
+
+	for module, level := range l.all() {
+		if module == "" {
+			defaultDebugLevel = level.String()
+		} else {
+			spec += fmt.Sprintf("%s=%s:", module, level.String())
+		}
+	}
+
+	for _, override := range l.GetLevelOverrides() {
+		spec += fmt.Sprintf("%s=%s@%s:", override.Module, override.Level.String(), remaining(override.Expiry))
+	}
+
+	return spec + defaultDebugLevel
+}
+
+// LevelSnapshot holds the log levels and the temporary level overrides of a level registry.
+type LevelSnapshot struct {
+	levels    map[string]Level
+	overrides []LevelOverride
+}
+
+// Snapshot returns the current log levels and temporary level overrides of the registry.
+func (l *LevelRegistry) Snapshot() *LevelSnapshot {
+	return &LevelSnapshot{
+		levels:    l.all(),
+		overrides: l.GetLevelOverrides(),
+	}
+}
+
+// Restore replaces the log levels and temporary level overrides of the registry with the given snapshot.
+// Overrides which have expired since the snapshot was taken are not restored. The level change listeners
+// are notified of the resulting level changes.
+func (l *LevelRegistry) Restore(snapshot *LevelSnapshot) {
+	l.update(func() {
+		for module := range l.overrides {
+			l.removeOverride(module)
+		}
+
+		l.levels = make(map[string]Level, len(snapshot.levels))
+
+		for module, level := range snapshot.levels {
+			l.levels[module] = level
+		}
+
+		for _, o := range snapshot.overrides {
+			if o.Expiry.After(time.Now()) {
+				l.setOverrideUntil(o.Module, o.Level, o.Expiry)
+			}
+		}
+	})
+}
+
+// SnapshotLevels returns the current log levels and temporary level overrides of the global registry.
+// It may be used together with RestoreLevels in order to prevent tests from affecting each other, for example:
+//
+//	defer log.RestoreLevels(log.SnapshotLevels())
+func SnapshotLevels() *LevelSnapshot {
+	return levels.Snapshot()
+}
+
+// RestoreLevels restores the log levels and temporary level overrides of the global registry
+// from the given snapshot.
+func RestoreLevels(snapshot *LevelSnapshot) {
+	levels.Restore(snapshot)
+}
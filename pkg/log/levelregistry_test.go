@@ -0,0 +1,143 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package log
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLevelRegistry(t *testing.T) {
+	const module = "level-registry-module"
+
+	SetLevel(module, ERROR)
+
+	tenant1 := NewLevelRegistry()
+	tenant2 := NewLevelRegistry()
+
+	require.NoError(t, tenant1.SetSpec(module+"=debug:warn"))
+	tenant2.SetDefaultLevel(ERROR)
+
+	output1 := newMockWriter()
+	output2 := newMockWriter()
+
+	logger1 := New(module, WithLevelRegistry(tenant1), WithRoute(output1, DEBUG, FATAL))
+	logger2 := New(module, WithLevelRegistry(tenant2), WithRoute(output2, DEBUG, FATAL))
+
+	logger1.Debug("Tenant 1 debug log")
+	logger1.Infoc(context.Background(), "Tenant 1 info log")
+	logger2.Warnc(context.Background(), "Tenant 2 warn log")
+
+	require.Contains(t, output1.String(), "Tenant 1 debug log")
+	require.Contains(t, output1.String(), "Tenant 1 info log")
+	require.Empty(t, output2.String())
+
+	require.True(t, logger1.With().IsEnabled(DEBUG))
+	require.False(t, logger2.IsEnabled(WARNING))
+
+	require.Equal(t, DEBUG, tenant1.GetLevel(module))
+	require.Equal(t, WARNING, tenant1.GetLevel("other-module"))
+	require.Equal(t, ERROR, GetLevel(module))
+	require.Equal(t, module+"=DEBUG:WARN", tenant1.GetSpec())
+
+	t.Run("configure", func(t *testing.T) {
+		resetDefaults(t)
+
+		// The level registry of a logger isn't changed by Configure.
+		require.NoError(t, Configure(WithLevelRegistry(tenant2)))
+
+		logger1.Debug("Tenant 1 debug log after configure")
+		require.Contains(t, output1.String(), "Tenant 1 debug log after configure")
+	})
+}
+
+func TestLevelSnapshot(t *testing.T) {
+	const module = "level-snapshot-module"
+
+	registry := NewLevelRegistry()
+	registry.SetLevel(module, ERROR)
+	registry.SetLevelFor(module+"-override", DEBUG, time.Minute)
+
+	snapshot := registry.Snapshot()
+
+	var changes []Level
+
+	unsubscribe := registry.OnLevelChange(module, func(_ string, _, newLevel Level) {
+		changes = append(changes, newLevel)
+	})
+	defer unsubscribe()
+
+	registry.SetLevel(module, DEBUG)
+	registry.SetLevel(module+"-other", DEBUG)
+	registry.SetLevelFor(module+"-override", DEBUG, 0)
+	registry.SetLevelFor(module+"-other-override", DEBUG, time.Minute)
+
+	registry.Restore(snapshot)
+
+	require.Equal(t, ERROR, registry.GetLevel(module))
+	require.Equal(t, INFO, registry.GetLevel(module+"-other"))
+	require.Equal(t, DEBUG, registry.GetLevel(module+"-override"))
+	require.Equal(t, INFO, registry.GetLevel(module+"-other-override"))
+	require.Equal(t, []Level{DEBUG, ERROR}, changes)
+
+	overrides := registry.GetLevelOverrides()
+	require.Len(t, overrides, 1)
+	require.Equal(t, snapshot.overrides[0].Expiry, overrides[0].Expiry)
+
+	t.Run("global", func(t *testing.T) {
+		snapshot := SnapshotLevels()
+
+		SetLevel(module, DEBUG)
+		RestoreLevels(snapshot)
+
+		require.Equal(t, INFO, GetLevel(module))
+	})
+}
+
+func TestLevelHierarchy(t *testing.T) {
+	registry := NewLevelRegistry()
+
+	registry.SetDefaultLevel(ERROR)
+	registry.SetLevel("a", DEBUG)
+	registry.SetLevel("a.b.c", WARNING)
+
+	require.Equal(t, DEBUG, registry.GetLevel("a.b"))
+	require.Equal(t, WARNING, registry.GetLevel("a.b.c"))
+	require.Equal(t, WARNING, registry.GetLevel("a.b.c.d"))
+	require.Equal(t, ERROR, registry.GetLevel("ab"))
+
+	registry.SetLevelFor("a.b", TRACE, time.Minute)
+
+	require.Equal(t, TRACE, registry.GetLevel("a.b.x"))
+	require.Equal(t, WARNING, registry.GetLevel("a.b.c"))
+	require.Equal(t, DEBUG, registry.GetLevel("a.x"))
+}
+
+func TestLevelHierarchyDottedModule(t *testing.T) {
+	registry := NewLevelRegistry()
+
+	require.NoError(t, registry.SetSpec("payments=debug:error"))
+
+	output := newMockWriter()
+
+	// The module isn't created with Named, but its level is still inherited from the parent module
+	// rather than from the default level.
+	logger := New("payments.gateway", WithLevelRegistry(registry), WithRoute(output, TRACE, FATAL))
+
+	logger.Debug("Sample debug log")
+	require.Contains(t, output.String(), "Sample debug log")
+
+	// A level which is set for the module itself takes precedence.
+	registry.SetLevel("payments.gateway", ERROR)
+
+	output.Reset()
+	logger.Debug("Sample debug log")
+	require.Empty(t, output.String())
+}
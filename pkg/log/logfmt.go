@@ -0,0 +1,460 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package log
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+var logfmtBufferPool = buffer.NewPool() //nolint:gochecknoglobals
+
+// logfmtEncoder is a zapcore.Encoder that outputs log entries as logfmt (key=value pairs separated by spaces).
+// Values are quoted (and escaped) if they contain spaces, '=', '"' or control characters. Nested objects
+// and arrays are flattened using dotted keys, for example: request.method=GET tags.0=a tags.1=b.
+type logfmtEncoder struct {
+	*zapcore.EncoderConfig
+	buf    *buffer.Buffer
+	prefix string
+}
+
+func newLogfmtEncoder(cfg zapcore.EncoderConfig) zapcore.Encoder {
+	return &logfmtEncoder{
+		EncoderConfig: &cfg,
+		buf:           logfmtBufferPool.Get(),
+	}
+}
+
+func (e *logfmtEncoder) AddArray(key string, marshaler zapcore.ArrayMarshaler) error {
+	return marshaler.MarshalLogArray(&logfmtArrayEncoder{enc: e, key: e.fullKey(key), indexed: true})
+}
+
+func (e *logfmtEncoder) AddObject(key string, marshaler zapcore.ObjectMarshaler) error {
+	return e.addObjectAt(e.fullKey(key), marshaler)
+}
+
+func (e *logfmtEncoder) AddBinary(key string, value []byte) {
+	e.AddString(key, base64.StdEncoding.EncodeToString(value))
+}
+
+func (e *logfmtEncoder) AddByteString(key string, value []byte) {
+	e.writeKey(e.fullKey(key))
+	e.appendString(string(value))
+}
+
+func (e *logfmtEncoder) AddBool(key string, value bool) {
+	e.writeKey(e.fullKey(key))
+	e.buf.AppendBool(value)
+}
+
+func (e *logfmtEncoder) AddComplex128(key string, value complex128) {
+	e.writeKey(e.fullKey(key))
+	e.buf.AppendString(strconv.FormatComplex(value, 'g', -1, 128))
+}
+
+func (e *logfmtEncoder) AddComplex64(key string, value complex64) {
+	e.writeKey(e.fullKey(key))
+	e.buf.AppendString(strconv.FormatComplex(complex128(value), 'g', -1, 64))
+}
+
+func (e *logfmtEncoder) AddDuration(key string, value time.Duration) {
+	e.addDurationAt(e.fullKey(key), value)
+}
+
+func (e *logfmtEncoder) AddFloat64(key string, value float64) {
+	e.writeKey(e.fullKey(key))
+	e.buf.AppendFloat(value, 64)
+}
+
+func (e *logfmtEncoder) AddFloat32(key string, value float32) {
+	e.writeKey(e.fullKey(key))
+	e.buf.AppendFloat(float64(value), 32)
+}
+
+func (e *logfmtEncoder) AddInt(key string, value int) {
+	e.AddInt64(key, int64(value))
+}
+
+func (e *logfmtEncoder) AddInt64(key string, value int64) {
+	e.writeKey(e.fullKey(key))
+	e.buf.AppendInt(value)
+}
+
+func (e *logfmtEncoder) AddInt32(key string, value int32) {
+	e.AddInt64(key, int64(value))
+}
+
+func (e *logfmtEncoder) AddInt16(key string, value int16) {
+	e.AddInt64(key, int64(value))
+}
+
+func (e *logfmtEncoder) AddInt8(key string, value int8) {
+	e.AddInt64(key, int64(value))
+}
+
+func (e *logfmtEncoder) AddString(key, value string) {
+	e.writeKey(e.fullKey(key))
+	e.appendString(value)
+}
+
+func (e *logfmtEncoder) AddTime(key string, value time.Time) {
+	e.addTimeAt(e.fullKey(key), value)
+}
+
+func (e *logfmtEncoder) AddUint(key string, value uint) {
+	e.AddUint64(key, uint64(value))
+}
+
+func (e *logfmtEncoder) AddUint64(key string, value uint64) {
+	e.writeKey(e.fullKey(key))
+	e.buf.AppendUint(value)
+}
+
+func (e *logfmtEncoder) AddUint32(key string, value uint32) {
+	e.AddUint64(key, uint64(value))
+}
+
+func (e *logfmtEncoder) AddUint16(key string, value uint16) {
+	e.AddUint64(key, uint64(value))
+}
+
+func (e *logfmtEncoder) AddUint8(key string, value uint8) {
+	e.AddUint64(key, uint64(value))
+}
+
+func (e *logfmtEncoder) AddUintptr(key string, value uintptr) {
+	e.AddUint64(key, uint64(value))
+}
+
+func (e *logfmtEncoder) AddReflected(key string, value interface{}) error {
+	return e.addReflectedAt(e.fullKey(key), value)
+}
+
+func (e *logfmtEncoder) OpenNamespace(key string) {
+	e.prefix = e.fullKey(key) + "."
+}
+
+func (e *logfmtEncoder) Clone() zapcore.Encoder {
+	clone := e.clone()
+	_, _ = clone.buf.Write(e.buf.Bytes()) //nolint:errcheck
+
+	return clone
+}
+
+func (e *logfmtEncoder) clone() *logfmtEncoder {
+	return &logfmtEncoder{
+		EncoderConfig: e.EncoderConfig,
+		buf:           logfmtBufferPool.Get(),
+		prefix:        e.prefix,
+	}
+}
+
+//nolint:cyclop
+func (e *logfmtEncoder) EncodeEntry(entry zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	final := &logfmtEncoder{
+		EncoderConfig: e.EncoderConfig,
+		buf:           logfmtBufferPool.Get(),
+	}
+
+	if final.TimeKey != "" {
+		final.addTimeAt(final.TimeKey, entry.Time)
+	}
+
+	if final.LevelKey != "" && final.EncodeLevel != nil {
+		final.encodeAt(final.LevelKey, func(enc zapcore.PrimitiveArrayEncoder) {
+			final.EncodeLevel(entry.Level, enc)
+		})
+	}
+
+	if final.NameKey != "" && entry.LoggerName != "" {
+		// The console name encoder (brackets) doesn't make sense for logfmt.
+		final.AddString(final.NameKey, entry.LoggerName)
+	}
+
+	if final.CallerKey != "" && entry.Caller.Defined && final.EncodeCaller != nil {
+		final.encodeAt(final.CallerKey, func(enc zapcore.PrimitiveArrayEncoder) {
+			final.EncodeCaller(entry.Caller, enc)
+		})
+	}
+
+	if final.FunctionKey != "" && entry.Caller.Defined {
+		final.AddString(final.FunctionKey, entry.Caller.Function)
+	}
+
+	if final.MessageKey != "" {
+		final.AddString(final.MessageKey, entry.Message)
+	}
+
+	if e.buf.Len() > 0 {
+		if final.buf.Len() > 0 {
+			final.buf.AppendByte(' ')
+		}
+
+		_, _ = final.buf.Write(e.buf.Bytes()) //nolint:errcheck
+	}
+
+	final.prefix = e.prefix
+
+	for i := range fields {
+		fields[i].AddTo(final)
+	}
+
+	final.prefix = ""
+
+	if final.StacktraceKey != "" && entry.Stack != "" {
+		final.AddString(final.StacktraceKey, entry.Stack)
+	}
+
+	if final.LineEnding != "" {
+		final.buf.AppendString(final.LineEnding)
+	} else {
+		final.buf.AppendString(zapcore.DefaultLineEnding)
+	}
+
+	return final.buf, nil
+}
+
+func (e *logfmtEncoder) fullKey(key string) string {
+	return e.prefix + sanitizeLogfmtKey(key)
+}
+
+func (e *logfmtEncoder) writeKey(fullKey string) {
+	if e.buf.Len() > 0 {
+		e.buf.AppendByte(' ')
+	}
+
+	e.buf.AppendString(fullKey)
+	e.buf.AppendByte('=')
+}
+
+func (e *logfmtEncoder) appendString(value string) {
+	if needsLogfmtQuoting(value) {
+		e.buf.AppendString(strconv.Quote(value))
+	} else {
+		e.buf.AppendString(value)
+	}
+}
+
+// encodeAt invokes the given encoder function (for example, a zapcore.TimeEncoder) with
+// an array encoder that outputs each appended value using the given key.
+func (e *logfmtEncoder) encodeAt(fullKey string, encode func(enc zapcore.PrimitiveArrayEncoder)) bool {
+	n := e.buf.Len()
+
+	encode(&logfmtArrayEncoder{enc: e, key: fullKey})
+
+	return e.buf.Len() != n
+}
+
+func (e *logfmtEncoder) addTimeAt(fullKey string, value time.Time) {
+	if e.EncodeTime != nil && e.encodeAt(fullKey, func(enc zapcore.PrimitiveArrayEncoder) {
+		e.EncodeTime(value, enc)
+	}) {
+		return
+	}
+
+	e.writeKey(fullKey)
+	e.buf.AppendInt(value.UnixNano())
+}
+
+func (e *logfmtEncoder) addDurationAt(fullKey string, value time.Duration) {
+	if e.EncodeDuration != nil && e.encodeAt(fullKey, func(enc zapcore.PrimitiveArrayEncoder) {
+		e.EncodeDuration(value, enc)
+	}) {
+		return
+	}
+
+	e.writeKey(fullKey)
+	e.buf.AppendInt(int64(value))
+}
+
+func (e *logfmtEncoder) addObjectAt(fullKey string, marshaler zapcore.ObjectMarshaler) error {
+	prefix := e.prefix
+	e.prefix = fullKey + "."
+
+	err := marshaler.MarshalLogObject(e)
+
+	e.prefix = prefix
+
+	return err
+}
+
+func (e *logfmtEncoder) addReflectedAt(fullKey string, value interface{}) error {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	e.writeKey(fullKey)
+	e.appendString(string(b))
+
+	return nil
+}
+
+// logfmtArrayEncoder outputs array elements using the key of the array suffixed with the index of
+// the element (if indexed) or, if not indexed, using the key of the array for each element.
+type logfmtArrayEncoder struct {
+	enc     *logfmtEncoder
+	key     string
+	indexed bool
+	index   int
+}
+
+func (a *logfmtArrayEncoder) next() string {
+	if !a.indexed {
+		return a.key
+	}
+
+	key := a.key + "." + strconv.Itoa(a.index)
+	a.index++
+
+	return key
+}
+
+func (a *logfmtArrayEncoder) AppendBool(value bool) {
+	a.enc.writeKey(a.next())
+	a.enc.buf.AppendBool(value)
+}
+
+func (a *logfmtArrayEncoder) AppendByteString(value []byte) {
+	a.AppendString(string(value))
+}
+
+func (a *logfmtArrayEncoder) AppendComplex128(value complex128) {
+	a.enc.writeKey(a.next())
+	a.enc.buf.AppendString(strconv.FormatComplex(value, 'g', -1, 128))
+}
+
+func (a *logfmtArrayEncoder) AppendComplex64(value complex64) {
+	a.enc.writeKey(a.next())
+	a.enc.buf.AppendString(strconv.FormatComplex(complex128(value), 'g', -1, 64))
+}
+
+func (a *logfmtArrayEncoder) AppendFloat64(value float64) {
+	a.enc.writeKey(a.next())
+	a.enc.buf.AppendFloat(value, 64)
+}
+
+func (a *logfmtArrayEncoder) AppendFloat32(value float32) {
+	a.enc.writeKey(a.next())
+	a.enc.buf.AppendFloat(float64(value), 32)
+}
+
+func (a *logfmtArrayEncoder) AppendInt(value int) {
+	a.AppendInt64(int64(value))
+}
+
+func (a *logfmtArrayEncoder) AppendInt64(value int64) {
+	a.enc.writeKey(a.next())
+	a.enc.buf.AppendInt(value)
+}
+
+func (a *logfmtArrayEncoder) AppendInt32(value int32) {
+	a.AppendInt64(int64(value))
+}
+
+func (a *logfmtArrayEncoder) AppendInt16(value int16) {
+	a.AppendInt64(int64(value))
+}
+
+func (a *logfmtArrayEncoder) AppendInt8(value int8) {
+	a.AppendInt64(int64(value))
+}
+
+func (a *logfmtArrayEncoder) AppendString(value string) {
+	a.enc.writeKey(a.next())
+	a.enc.appendString(value)
+}
+
+func (a *logfmtArrayEncoder) AppendUint(value uint) {
+	a.AppendUint64(uint64(value))
+}
+
+func (a *logfmtArrayEncoder) AppendUint64(value uint64) {
+	a.enc.writeKey(a.next())
+	a.enc.buf.AppendUint(value)
+}
+
+func (a *logfmtArrayEncoder) AppendUint32(value uint32) {
+	a.AppendUint64(uint64(value))
+}
+
+func (a *logfmtArrayEncoder) AppendUint16(value uint16) {
+	a.AppendUint64(uint64(value))
+}
+
+func (a *logfmtArrayEncoder) AppendUint8(value uint8) {
+	a.AppendUint64(uint64(value))
+}
+
+func (a *logfmtArrayEncoder) AppendUintptr(value uintptr) {
+	a.AppendUint64(uint64(value))
+}
+
+func (a *logfmtArrayEncoder) AppendDuration(value time.Duration) {
+	a.enc.addDurationAt(a.next(), value)
+}
+
+func (a *logfmtArrayEncoder) AppendTime(value time.Time) {
+	a.enc.addTimeAt(a.next(), value)
+}
+
+func (a *logfmtArrayEncoder) AppendArray(marshaler zapcore.ArrayMarshaler) error {
+	return marshaler.MarshalLogArray(&logfmtArrayEncoder{enc: a.enc, key: a.next(), indexed: true})
+}
+
+func (a *logfmtArrayEncoder) AppendObject(marshaler zapcore.ObjectMarshaler) error {
+	return a.enc.addObjectAt(a.next(), marshaler)
+}
+
+func (a *logfmtArrayEncoder) AppendReflected(value interface{}) error {
+	return a.enc.addReflectedAt(a.next(), value)
+}
+
+// sanitizeLogfmtKey replaces the characters that are not allowed in a logfmt key with an underscore.
+func sanitizeLogfmtKey(key string) string {
+	if key == "" {
+		return "_"
+	}
+
+	if strings.IndexFunc(key, isInvalidLogfmtKeyRune) < 0 {
+		return key
+	}
+
+	return strings.Map(func(r rune) rune {
+		if isInvalidLogfmtKeyRune(r) {
+			return '_'
+		}
+
+		return r
+	}, key)
+}
+
+func isInvalidLogfmtKeyRune(r rune) bool {
+	return r <= ' ' || r == '=' || r == '"' || r == 0x7f || r == utf8.RuneError
+}
+
+// needsLogfmtQuoting returns true if the given value must be quoted.
+func needsLogfmtQuoting(value string) bool {
+	if value == "" {
+		return true
+	}
+
+	for _, r := range value {
+		if r <= ' ' || r == '=' || r == '"' || r == '\\' || r == 0x7f || r == utf8.RuneError {
+			return true
+		}
+	}
+
+	return false
+}
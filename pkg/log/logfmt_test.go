@@ -0,0 +1,111 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package log
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type logfmtRequest struct {
+	Method string
+	Path   string
+}
+
+func (r *logfmtRequest) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("method", r.Method)
+	enc.AddString("path", r.Path)
+
+	return nil
+}
+
+func TestLogfmtEncoding(t *testing.T) {
+	const module = "logfmt-module"
+
+	SetLevel(module, DEBUG)
+
+	t.Run("entry", func(t *testing.T) {
+		output := newMockWriter()
+
+		logger := New(module, WithEncoding(Logfmt), WithRoute(output, DEBUG, FATAL),
+			WithTimeFormat("epoch"), WithFields(zap.String("service", "my service")))
+
+		logger.Info("Sample info log", zap.Int("count", 3), zap.Bool("ok", true),
+			zap.String("empty", ""), zap.String("quote", `say "hi"`), zap.String("eq", "a=b"),
+			zap.Duration("elapsed", 1500*time.Millisecond), zap.Error(errors.New("some error")))
+
+		line := output.String()
+
+		require.True(t, strings.HasPrefix(line, "ts="))
+		require.True(t, strings.HasSuffix(line, "\n"))
+		require.Contains(t, line, " level=info logger=logfmt-module caller=log/logfmt_test.go:")
+		require.Contains(t, line, ` msg="Sample info log" service="my service" count=3 ok=true empty="" `)
+		require.Contains(t, line, ` quote="say \"hi\"" eq="a=b" elapsed=1.5s error="some error"`)
+	})
+
+	t.Run("nested", func(t *testing.T) {
+		output := newMockWriter()
+
+		logger := New(module, WithEncoding(Logfmt), WithRoute(output, DEBUG, FATAL),
+			WithEncoderKeys(EncoderKeys{Time: OmitKey, Caller: OmitKey}))
+
+		logger.With(zap.Namespace("ctx"), zap.String("id", "123")).Debug("msg",
+			zap.Object("request", &logfmtRequest{Method: "GET", Path: "/a b"}),
+			zap.Strings("tags", []string{"a", "b"}),
+			zap.Objects("reqs", []*logfmtRequest{{Method: "PUT", Path: "/c"}}),
+			zap.Any("map", map[string]int{"x": 1}),
+			zap.String("bad key", "\n"),
+		)
+
+		require.Equal(t, `level=debug logger=logfmt-module msg=msg ctx.id=123 ctx.request.method=GET `+
+			`ctx.request.path="/a b" ctx.tags.0=a ctx.tags.1=b ctx.reqs.0.method=PUT ctx.reqs.0.path=/c `+
+			`ctx.map="{\"x\":1}" ctx.bad_key="\n"`+"\n", output.String())
+	})
+
+	t.Run("tracing", func(t *testing.T) {
+		output := newMockWriter()
+
+		logger := New(module, WithEncoding(Logfmt), WithRoute(output, DEBUG, FATAL))
+
+		ctx, span := trace.NewTracerProvider().Tracer("unit-test").Start(context.Background(), "span")
+		defer span.End()
+
+		logger.Infoc(ctx, "Sample info log")
+
+		require.Contains(t, output.String(), " trace_id="+span.SpanContext().TraceID().String()+
+			" span_id="+span.SpanContext().SpanID().String())
+	})
+
+	t.Run("supported", func(t *testing.T) {
+		require.True(t, isSupportedEncoding(Logfmt))
+	})
+}
+
+func TestNeedsLogfmtQuoting(t *testing.T) {
+	for value, expected := range map[string]bool{
+		"":        true,
+		"abc":     false,
+		"a/b:c.d": false,
+		"a b":     true,
+		"a=b":     true,
+		`a"b`:     true,
+		`a\b`:     true,
+		"a\tb":    true,
+		"\xff":    true,
+		"héllo":   false,
+	} {
+		require.Equal(t, expected, needsLogfmtQuoting(value), value)
+	}
+}
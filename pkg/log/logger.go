@@ -11,9 +11,13 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
+	"go.opentelemetry.io/otel/metric"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -37,6 +41,8 @@ type Level int
 // String returns string representation of given log level.
 func (l Level) String() string {
 	switch l {
+	case TRACE:
+		return "TRACE"
 	case DEBUG:
 		return "DEBUG"
 	case INFO:
@@ -45,6 +51,8 @@ func (l Level) String() string {
 		return "WARN"
 	case ERROR:
 		return "ERROR"
+	case CRITICAL:
+		return "CRITICAL"
 	case PANIC:
 		return "PANIC"
 	case FATAL:
@@ -54,47 +62,82 @@ func (l Level) String() string {
 	}
 }
 
-// ParseLevel returns the level from the given string.
+// ParseLevel returns the level from the given string. The name of the level is case-insensitive
+// (for example, "debug" or "DEBUG"). The numeric value of a level (for example, "-1" for DEBUG) is also accepted.
 func ParseLevel(level string) (Level, error) {
-	switch level {
-	case "DEBUG", "debug":
+	switch strings.ToUpper(level) {
+	case "TRACE":
+		return TRACE, nil
+	case "DEBUG":
 		return DEBUG, nil
-	case "INFO", "info":
+	case "INFO":
 		return INFO, nil
-	case "WARN", "warn", "WARNING", "warning":
+	case "WARN", "WARNING":
 		return WARNING, nil
-	case "ERROR", "error":
+	case "ERROR":
 		return ERROR, nil
-	case "PANIC", "panic":
+	case "CRITICAL":
+		return CRITICAL, nil
+	case "PANIC":
 		return PANIC, nil
-	case "FATAL", "fatal":
+	case "FATAL":
 		return FATAL, nil
-	default:
-		return ERROR, errors.New("logger: invalid log level")
 	}
+
+	if n, err := strconv.Atoi(level); err == nil && Level(n) >= minLogLevel && Level(n) <= maxLogLevel {
+		return Level(n), nil
+	}
+
+	return ERROR, errors.New("logger: invalid log level")
 }
 
 // Log levels.
 const (
+	// TRACE is below DEBUG and is intended for very verbose logs, such as dumps of protocol messages.
+	TRACE   = Level(zapcore.DebugLevel - 1)
 	DEBUG   = Level(zapcore.DebugLevel)
 	INFO    = Level(zapcore.InfoLevel)
 	WARNING = Level(zapcore.WarnLevel)
 	ERROR   = Level(zapcore.ErrorLevel)
-	PANIC   = Level(zapcore.PanicLevel)
-	FATAL   = Level(zapcore.FatalLevel)
+	// CRITICAL is between ERROR and PANIC. It corresponds to the DPanic level of zap, but the logger doesn't panic.
+	CRITICAL = Level(zapcore.DPanicLevel)
+	PANIC    = Level(zapcore.PanicLevel)
+	FATAL    = Level(zapcore.FatalLevel)
 
-	minLogLevel  = DEBUG
+	minLogLevel  = TRACE
+	maxLogLevel  = FATAL
 	defaultLevel = INFO
 )
 
-var levels = newModuleLevels() //nolint: gochecknoglobals
+var (
+	levels   = NewLevelRegistry()  //nolint: gochecknoglobals
+	defaults = newDefaultOptions() //nolint: gochecknoglobals
+)
 
 type options struct {
-	encoding   Encoding
-	stdOut     zapcore.WriteSyncer
-	stdErr     zapcore.WriteSyncer
-	fields     []zap.Field
-	callerSkip int
+	encoding      Encoding
+	stdOut        zapcore.WriteSyncer
+	stdErr        zapcore.WriteSyncer
+	fields        []zap.Field
+	callerSkip    int
+	timeEncoder   zapcore.TimeEncoder
+	encoderKeys   *EncoderKeys
+	spanEvents    bool
+	meterProvider metric.MeterProvider
+
+	routes         []*route
+	routesSet      bool
+	sampling       *samplingOptions
+	redactionRules []*redactionRule
+	staticFields   []zap.Field
+
+	flightRecorderSize int
+	flightRecorder     *flightRecorder
+	stacktrace         *stacktraceOptions
+	ignoreModuleLevels bool
+	levels             *LevelRegistry
+	hooks              []Hook
+	hooksSet           bool
 }
 
 // Encoding defines the log encoding.
@@ -104,6 +147,10 @@ type Encoding = string
 const (
 	Console Encoding = "console"
 	JSON    Encoding = "json"
+	// Logfmt outputs key=value pairs. Nested objects are flattened using dotted keys.
+	Logfmt Encoding = "logfmt"
+	// Pretty is a human-friendly (and, on a terminal, colorized) encoding for local development.
+	Pretty Encoding = "pretty"
 )
 
 const defaultModuleName = ""
@@ -111,17 +158,23 @@ const defaultModuleName = ""
 // Option is a logger option.
 type Option func(o *options)
 
-// WithStdOut sets the output for logs of type DEBUG, INFO, and WARN.
+// WithStdOut sets the output for logs of type TRACE, DEBUG, INFO, and WARN. This is a shortcut for the
+// default routing and therefore replaces any routes that were configured with WithRoute or ApplyConfig.
 func WithStdOut(stdOut zapcore.WriteSyncer) Option {
 	return func(o *options) {
 		o.stdOut = stdOut
+		o.routes = nil
+		o.routesSet = false
 	}
 }
 
-// WithStdErr sets the output for logs of type ERROR, PANIC, and FATAL.
+// WithStdErr sets the output for logs of type ERROR, CRITICAL, PANIC, and FATAL. This is a shortcut for the
+// default routing and therefore replaces any routes that were configured with WithRoute or ApplyConfig.
 func WithStdErr(stdErr zapcore.WriteSyncer) Option {
 	return func(o *options) {
 		o.stdErr = stdErr
+		o.routes = nil
+		o.routesSet = false
 	}
 }
 
@@ -132,7 +185,7 @@ func WithFields(fields ...zap.Field) Option {
 	}
 }
 
-// WithEncoding sets the output encoding (console or json).
+// WithEncoding sets the output encoding (json, console, logfmt, pretty, or one of the vendor encodings: gcp, ecs, datadog).
 func WithEncoding(encoding Encoding) Option {
 	return func(o *options) {
 		o.encoding = encoding
@@ -146,31 +199,146 @@ func WithCallerSkip(callerSkip int) Option {
 	}
 }
 
+// WithTimeFormat sets the format of the timestamp. The format may be one of: iso8601, rfc3339, rfc3339nano,
+// epoch, millis, nanos, or a Go time layout (for example, "2006-01-02 15:04:05.000"). If the format is
+// invalid then the default (iso8601) is used.
+func WithTimeFormat(format string) Option {
+	return func(o *options) {
+		timeEncoder, err := parseTimeFormat(format)
+		if err != nil {
+			return
+		}
+
+		o.timeEncoder = timeEncoder
+	}
+}
+
+// WithSpanEvents enables the recording of WARN, ERROR, CRITICAL, PANIC and FATAL context logs (Warnc, Errorc, etc.)
+// as events on the span found in the given context. At ERROR level and above the span status is set to
+// Error, and errors provided with WithError are recorded using span.RecordError.
+func WithSpanEvents() Option {
+	return func(o *options) {
+		o.spanEvents = true
+	}
+}
+
+// WithMetrics enables OpenTelemetry metrics for the logger using the given meter provider. The counter
+// 'log.entries' is incremented for each log entry that is written and the counter 'log.entries.suppressed'
+// is incremented for each log entry that is suppressed by the log level. Entries which are dropped by sampling
+// (or which match no route) are not counted. Both counters have the attributes 'module' and 'level'.
+func WithMetrics(meterProvider metric.MeterProvider) Option {
+	return func(o *options) {
+		o.meterProvider = meterProvider
+	}
+}
+
 // Log uses the Zap Logger to log messages in a structured way. Functions are also included to
 // log context-specific fields, such as OpenTelemetry trace and span IDs.
 type Log struct {
 	*zap.Logger
+	// ctxLogger shares the core (and therefore the fields) of the logger and only differs in the caller skip.
 	ctxLogger *zap.Logger
-	module    string
+	// overrideLogger is used for context logs which are enabled by a context level override
+	// (see EnableBaggageLevel). It is created on first use.
+	overrideLogger *lazyLogger
+	module         string
+	callerSkip     int
+	levels         *LevelRegistry
+	// opts and fields are used to create named children of the logger (see Named).
+	opts   []Option
+	fields []zap.Field
+	// named caches the named children of the logger. It is shared with the children created with With.
+	named *namedLoggers
+	// registration is shared with the children of the logger. It keeps the logger in the registry
+	// of live loggers (see Sync).
+	registration *registration
 }
 
 // New creates a Zap Logger to log messages in a structured way.
 func New(module string, opts ...Option) *Log {
 	options := getOptions(opts)
 
+	// The level registry is resolved once so that it isn't changed by Configure.
+	opts = append(opts[:len(opts):len(opts)], WithLevelRegistry(options.levelRegistry()))
+
+	var loggerOpts []zap.Option
+
+	if options.spanEvents {
+		// Only the entries of context logs are recorded since other entries have no tracing field.
+		loggerOpts = append(loggerOpts, zap.WrapCore(newSpanEventCore))
+	}
+
+	var metrics *logMetrics
+
+	if options.meterProvider != nil {
+		metrics = newLogMetrics(module, options.meterProvider)
+
+		loggerOpts = append(loggerOpts, zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+			return newMetricsCore(core, metrics)
+		}))
+	}
+
+	// The hooks are called first so that dropped entries aren't recorded or counted. The hooks are resolved
+	// when an entry is written since the default hooks may be changed by Configure.
+	hooks := &hookProvider{opts: opts}
+
+	if options.flightRecorderSize > 0 {
+		recorder := options.flightRecorder
+		if recorder == nil {
+			recorder = newFlightRecorder(options.flightRecorderSize, flightRecorderMaxKeys)
+
+			// The named children of the logger share the flight recorder.
+			opts = append(opts[:len(opts):len(opts)], withFlightRecorderOf(recorder))
+		}
+
+		// The buffered entries are written to the routes regardless of the level of the module.
+		var flushCore zapcore.Core = newReconfigurableCore(module,
+			append(opts[:len(opts):len(opts)], ignoreModuleLevels())...)
+
+		if metrics != nil {
+			// The flushed entries are counted as written.
+			flushCore = newMetricsCore(flushCore, metrics)
+		}
+
+		// The hooks are called for the buffered entries when they are flushed.
+		flushCore = newHookCore(flushCore, module, hooks)
+
+		// The flight recorder wraps the metrics core so that buffered entries are counted as suppressed.
+		loggerOpts = append(loggerOpts, zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+			return newFlightRecorderCore(core, flushCore, recorder)
+		}))
+	}
+
+	loggerOpts = append(loggerOpts, zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return newHookCore(core, module, hooks)
+	}))
+
+	logger := newZap(module, opts).
+		WithOptions(loggerOpts...).
+		With(options.fields...)
+
 	return &Log{
-		Logger: newZap(module, options.encoding, options.stdOut, options.stdErr).
-			With(options.fields...),
-		ctxLogger: newZap(module, options.encoding, options.stdOut, options.stdErr).
-			WithOptions(zap.AddCallerSkip(options.callerSkip)).
-			With(options.fields...),
-		module: module,
+		Logger:    logger,
+		ctxLogger: logger.WithOptions(zap.AddCallerSkip(options.callerSkip)),
+		overrideLogger: newLazyLogger(func() *zap.Logger {
+			return newZap(module, append(opts[:len(opts):len(opts)], ignoreModuleLevels())).
+				WithOptions(loggerOpts...).
+				With(options.fields...).
+				WithOptions(zap.AddCallerSkip(options.callerSkip))
+		}),
+		module:     module,
+		callerSkip: options.callerSkip,
+		levels:     options.levelRegistry(),
+		opts:       opts,
+		named:      &namedLoggers{loggers: make(map[string]*Log)},
+		// The context logger shares the core of the logger, so syncing the core of the logger is sufficient.
+		registration: loggers.register(module, logger.Core()),
 	}
 }
 
 // IsEnabled returns true if given log level is enabled.
 func (l *Log) IsEnabled(level Level) bool {
-	return levels.isEnabled(l.module, level)
+	return l.levels.isEnabled(l.module, level)
 }
 
 // With creates a child logger and adds structured context to it. Fields added
@@ -180,35 +348,115 @@ func (l *Log) With(fields ...zap.Field) *Log {
 		return l
 	}
 
+	logger := l.Logger.With(fields...)
+
 	return &Log{
-		Logger:    l.Logger.With(fields...),
-		ctxLogger: l.ctxLogger.With(fields...),
-		module:    l.module,
+		Logger:    logger,
+		ctxLogger: logger.WithOptions(zap.AddCallerSkip(l.callerSkip)),
+		overrideLogger: newLazyLogger(func() *zap.Logger {
+			return l.overrideLogger.get().With(fields...)
+		}),
+		module:       l.module,
+		callerSkip:   l.callerSkip,
+		levels:       l.levels,
+		opts:         l.opts,
+		fields:       append(l.fields[:len(l.fields):len(l.fields)], fields...),
+		named:        l.named,
+		registration: l.registration,
+	}
+}
+
+// Named creates a child logger for the module "<module>.<name>" (or "<name>" for the default module),
+// which is used as the logger name of the entries. The child has the options and fields of the parent,
+// but its level is that of the child module, which inherits the level of the parent module unless a
+// level is set for the child module. The children are cached by name (so the name should not be derived
+// from the request, for example), which makes Named as cheap as With after the first call. This allows
+// the subsystems of a module to be tuned individually:
+//
+//	logger := log.New("vcs")
+//	issuer := logger.Named("issuer") // logs as "vcs.issuer"
+//
+//	log.SetLevel("vcs.issuer", log.DEBUG)
+func (l *Log) Named(name string) *Log {
+	if name == "" {
+		return l
+	}
+
+	return l.named.get(l, name).With(l.fields...)
+}
+
+// namedLoggers holds the named children of a logger, without the fields added to the logger with With.
+type namedLoggers struct {
+	loggers map[string]*Log
+	mutex   sync.Mutex
+}
+
+func (n *namedLoggers) get(parent *Log, name string) *Log {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	if logger, ok := n.loggers[name]; ok {
+		return logger
+	}
+
+	module := name
+	if parent.module != defaultModuleName {
+		module = parent.module + "." + name
+	}
+
+	logger := New(module, parent.opts...)
+
+	n.loggers[name] = logger
+
+	return logger
+}
+
+// Tracec logs a message at Trace level, including the provided fields and any implicit context
+// fields (such as OpenTelemetry trace ID and span ID).
+func (l *Log) Tracec(ctx context.Context, msg string, fields ...zap.Field) {
+	if ce := l.ctxLoggerFor(ctx, TRACE).Check(zapcore.Level(TRACE), msg); ce != nil {
+		ce.Write(withTracingField(ctx, fields)...)
 	}
 }
 
 // Debugc logs a message at Debug level, including the provided fields and any implicit context
 // fields (such as OpenTelemetry trace ID and span ID).
 func (l *Log) Debugc(ctx context.Context, msg string, fields ...zap.Field) {
-	l.ctxLogger.Debug(msg, append(fields, WithTracing(ctx))...)
+	if ce := l.ctxLoggerFor(ctx, DEBUG).Check(zapcore.DebugLevel, msg); ce != nil {
+		ce.Write(withTracingField(ctx, fields)...)
+	}
 }
 
 // Infoc logs a message at Info level, including the provided fields and any implicit context
 // fields (such as OpenTelemetry trace ID and span ID).
 func (l *Log) Infoc(ctx context.Context, msg string, fields ...zap.Field) {
-	l.ctxLogger.Info(msg, append(fields, WithTracing(ctx))...)
+	if ce := l.ctxLoggerFor(ctx, INFO).Check(zapcore.InfoLevel, msg); ce != nil {
+		ce.Write(withTracingField(ctx, fields)...)
+	}
 }
 
 // Warnc logs a message at Warning level, including the provided fields and any implicit context
 // fields (such as OpenTelemetry trace ID and span ID).
 func (l *Log) Warnc(ctx context.Context, msg string, fields ...zap.Field) {
-	l.ctxLogger.Warn(msg, append(fields, WithTracing(ctx))...)
+	if ce := l.ctxLoggerFor(ctx, WARNING).Check(zapcore.WarnLevel, msg); ce != nil {
+		ce.Write(withTracingField(ctx, fields)...)
+	}
 }
 
 // Errorc logs a message at Error level, including the provided fields and any implicit context
 // fields (such as OpenTelemetry trace ID and span ID).
 func (l *Log) Errorc(ctx context.Context, msg string, fields ...zap.Field) {
-	l.ctxLogger.Error(msg, append(fields, WithTracing(ctx))...)
+	if ce := l.ctxLoggerFor(ctx, ERROR).Check(zapcore.ErrorLevel, msg); ce != nil {
+		ce.Write(withTracingField(ctx, fields)...)
+	}
+}
+
+// Criticalc logs a message at Critical level, including the provided fields and any implicit context
+// fields (such as OpenTelemetry trace ID and span ID).
+func (l *Log) Criticalc(ctx context.Context, msg string, fields ...zap.Field) {
+	if ce := l.ctxLoggerFor(ctx, CRITICAL).Check(zapcore.Level(CRITICAL), msg); ce != nil {
+		ce.Write(withTracingField(ctx, fields)...)
+	}
 }
 
 // Panicc logs a message at Panic level, including the provided fields and any implicit context
@@ -216,7 +464,9 @@ func (l *Log) Errorc(ctx context.Context, msg string, fields ...zap.Field) {
 //
 // The logger then panics, even if logging at PanicLevel is disabled.
 func (l *Log) Panicc(ctx context.Context, msg string, fields ...zap.Field) {
-	l.ctxLogger.Panic(msg, append(fields, WithTracing(ctx))...)
+	if ce := l.ctxLoggerFor(ctx, PANIC).Check(zapcore.PanicLevel, msg); ce != nil {
+		ce.Write(withTracingField(ctx, fields)...)
+	}
 }
 
 // Fatalc logs a message at Fatal level, including the provided fields and any implicit context
@@ -225,22 +475,96 @@ func (l *Log) Panicc(ctx context.Context, msg string, fields ...zap.Field) {
 // The logger then calls os.Exit(1), even if logging at FatalLevel is
 // disabled.
 func (l *Log) Fatalc(ctx context.Context, msg string, fields ...zap.Field) {
-	l.ctxLogger.Fatal(msg, append(fields, WithTracing(ctx))...)
+	if ce := l.ctxLoggerFor(ctx, FATAL).Check(zapcore.FatalLevel, msg); ce != nil {
+		ce.Write(withTracingField(ctx, fields)...)
+	}
+}
+
+// Logc logs a message at the given level, including the provided fields and any implicit context
+// fields (such as OpenTelemetry trace ID and span ID). This is useful if the level is computed, for
+// example from the status code of an HTTP response.
+//
+// As with Panicc and Fatalc, the logger panics (or calls os.Exit(1)) at PANIC (or FATAL) level.
+func (l *Log) Logc(ctx context.Context, level Level, msg string, fields ...zap.Field) {
+	if ce := l.ctxLoggerFor(ctx, level).Check(zapcore.Level(level), msg); ce != nil {
+		ce.Write(withTracingField(ctx, fields)...)
+	}
+}
+
+// CheckedEntry is a context log entry which is enabled (see Checkc).
+type CheckedEntry struct {
+	*zapcore.CheckedEntry
+	ctx context.Context
+}
+
+// Write writes the entry, including the provided fields and any implicit context fields (such as
+// OpenTelemetry trace ID and span ID).
+func (ce *CheckedEntry) Write(fields ...zap.Field) {
+	ce.CheckedEntry.Write(withTracingField(ce.ctx, fields)...)
+}
+
+// Checkc returns a CheckedEntry if logging a message at the given level is enabled, otherwise nil.
+// The fields of the entry are only created if the entry is enabled. The caller of Checkc is logged
+// as the caller of the entry. Example:
+//
+//	if ce := logger.Checkc(ctx, levelOf(err), "Request failed"); ce != nil {
+//		ce.Write(log.WithError(err), zap.Any("request", req))
+//	}
+func (l *Log) Checkc(ctx context.Context, level Level, msg string) *CheckedEntry {
+	ce := l.ctxLoggerFor(ctx, level).Check(zapcore.Level(level), msg)
+	if ce == nil {
+		return nil
+	}
+
+	return &CheckedEntry{CheckedEntry: ce, ctx: ctx}
+}
+
+// tracedFieldsSize is the maximum number of fields (including the tracing field) of a context log for which
+// the fields and the tracing field are allocated at once (see withTracingField).
+const tracedFieldsSize = 4
+
+// tracedFields holds the fields of a context log and the marshaller of its tracing field.
+type tracedFields struct {
+	marshaller otelMarshaller
+	fields     [tracedFieldsSize]zap.Field
 }
 
-// SetLevel sets the log level for given module and level.
+// withTracingField returns a copy of the given fields to which the tracing field is appended. The fields are
+// copied (rather than appended to) so that the variadic fields of the context logging functions don't escape
+// to the heap, and therefore aren't allocated, when the level is disabled. If there are only a few fields
+// then the copy and the marshaller of the tracing field are allocated at once, so that a context log
+// doesn't allocate more than a log without context (apart from the formatting of the trace and span IDs).
+func withTracingField(ctx context.Context, fields []zap.Field) []zap.Field {
+	if len(fields) < tracedFieldsSize {
+		t := &tracedFields{marshaller: otelMarshaller{ctx: ctx}}
+
+		return append(append(t.fields[:0], fields...), zap.Inline(&t.marshaller))
+	}
+
+	all := make([]zap.Field, 0, len(fields)+1)
+
+	return append(append(all, fields...), WithTracing(ctx))
+}
+
+// SetLevel sets the log level for given module and level. The level also applies to the child modules
+// (for example, "module.sub", see Log.Named) which don't have a level of their own. If a temporary level
+// override is active for the module (see SetLevelFor) then the given level takes effect when the override expires.
+//
+// Note that this is a change in behaviour for any module whose name contains a dot, including the modules
+// which aren't created with Named: the module "a.b" now inherits the level of "a" (rather than the default
+// level) unless a level is set for "a.b".
 func SetLevel(module string, level Level) {
-	levels.Set(module, level)
+	levels.SetLevel(module, level)
 }
 
 // SetDefaultLevel sets the default log level.
 func SetDefaultLevel(level Level) {
-	levels.SetDefault(level)
+	levels.SetDefaultLevel(level)
 }
 
 // GetLevel returns the log level for the given module.
 func GetLevel(module string) Level {
-	return levels.Get(module)
+	return levels.GetLevel(module)
 }
 
 // SetSpec sets the log levels for individual modules as well as the default log level.
@@ -248,12 +572,23 @@ func GetLevel(module string) Level {
 //
 //	module1=level1:module2=level2:module3=level3:defaultLevel
 //
-// Valid log levels are: critical, error, warning, info, debug
+// Valid log levels are: fatal, panic, critical, error, warning, info, debug, trace (in any case),
+// or the numeric value of a level.
+//
+// The level of a module may be followed by '@' and a duration in order to set a temporary
+// level override which expires after the given duration (see SetLevelFor).
+//
+// As with SetLevel, the level of a module also applies to its child modules which don't have a level
+// of their own, so "module1.sub" inherits the level of module1 (rather than the default level).
 //
 // Example:
 //
-//	module1=error:module2=debug:module3=warning:info
+//	module1=error:module2=debug@15m:module3=warning:info
 func SetSpec(spec string) error {
+	return levels.SetSpec(spec)
+}
+
+func parseSpec(spec string) (Level, []moduleLevelPair, error) {
 	logLevelByModule := strings.Split(spec, ":")
 
 	defaultLogLevel := minLogLevel - 1
@@ -264,38 +599,39 @@ func SetSpec(spec string) error {
 		if strings.Contains(logLevelByModulePart, "=") {
 			moduleAndLevelPair := strings.Split(logLevelByModulePart, "=")
 
-			logLevel, err := ParseLevel(moduleAndLevelPair[1])
+			levelAndTTL := strings.SplitN(moduleAndLevelPair[1], "@", 2)
+
+			logLevel, err := ParseLevel(levelAndTTL[0])
 			if err != nil {
-				return err
+				return defaultLogLevel, nil, err
+			}
+
+			pair := moduleLevelPair{module: moduleAndLevelPair[0], logLevel: logLevel}
+
+			if len(levelAndTTL) == 2 {
+				pair.ttl, err = time.ParseDuration(levelAndTTL[1])
+				if err != nil || pair.ttl <= 0 {
+					return defaultLogLevel, nil, fmt.Errorf("invalid duration for module %s: %s",
+						pair.module, levelAndTTL[1])
+				}
 			}
 
-			moduleLevelPairs = append(moduleLevelPairs,
-				moduleLevelPair{moduleAndLevelPair[0], logLevel})
+			moduleLevelPairs = append(moduleLevelPairs, pair)
 		} else {
 			if defaultLogLevel >= minLogLevel {
-				return errors.New("multiple default values found")
+				return defaultLogLevel, nil, errors.New("multiple default values found")
 			}
 
 			level, err := ParseLevel(logLevelByModulePart)
 			if err != nil {
-				return err
+				return defaultLogLevel, nil, err
 			}
 
 			defaultLogLevel = level
 		}
 	}
 
-	if defaultLogLevel >= minLogLevel {
-		levels.Set("", defaultLogLevel)
-	} else {
-		levels.Set("", INFO)
-	}
-
-	for _, moduleLevelPair := range moduleLevelPairs {
-		levels.Set(moduleLevelPair.module, moduleLevelPair.logLevel)
-	}
-
-	return nil
+	return defaultLogLevel, moduleLevelPairs, nil
 }
 
 // GetSpec returns the log spec which specifies the log level of each individual module. The spec is
@@ -303,163 +639,230 @@ func SetSpec(spec string) error {
 //
 //	module1=level1:module2=level2:module3=level3:defaultLevel
 //
+// Active temporary level overrides (see SetLevelFor) are included with their remaining duration.
+//
 // Example:
 //
-//	module1=error:module2=debug:module3=warning:info
+//	module1=error:module2=debug:module3=warning:module3=debug@14m59s:info
 func GetSpec() string {
-	var spec string
+	return levels.GetSpec()
+}
 
-	var defaultDebugLevel string
+type moduleLevelPair struct {
+	module   string
+	logLevel Level
+	ttl      time.Duration
+}
 
-	for module, level := range getAllLevels() {
-		if module == "" {
-			defaultDebugLevel = level.String()
+func newZap(module string, opts []Option) *zap.Logger {
+	return zap.New(newReconfigurableCore(module, opts...), zap.AddCaller()).Named(module)
+}
+
+func newZapCore(module string, o *options) zapcore.Core {
+	routes := o.routes
+	if len(routes) == 0 {
+		routes = defaultRoutes(o.stdOut, o.stdErr)
+	}
+
+	encoders := make(map[Encoding]zapcore.Encoder)
+	cores := make([]zapcore.Core, 0, len(routes))
+
+	for _, r := range routes {
+		if !r.matches(module) {
+			continue
+		}
+
+		encoding := r.encoding
+		if encoding == "" {
+			encoding = o.encoding
+		}
+
+		var core zapcore.Core
+
+		if r.core != nil {
+			core = newFilterCore(r.core, r.levelEnabler(module, o.levelRegistry(), o.ignoreModuleLevels))
 		} else {
-			spec += fmt.Sprintf("%s=%s:", module, level.String())
+			encoder, ok := encoders[encoding]
+			if !ok {
+				encoder = newZapEncoder(encoding, o.timeEncoder, o.encoderKeys)
+				encoders[encoding] = encoder
+			}
+
+			encoder, output := withColor(encoder, r.output)
+
+			core = zapcore.NewCore(encoder, zapcore.Lock(output), r.levelEnabler(module, o.levelRegistry(), o.ignoreModuleLevels))
+		}
+
+		if format := tracingFormatOf(encoding, o.encoderKeys); format != nil {
+			core = newTracingFormatCore(core, format)
+		}
+
+		if len(o.redactionRules) > 0 {
+			core = newRedactionCore(core, o.redactionRules)
 		}
+
+		cores = append(cores, core)
 	}
 
-	return spec + defaultDebugLevel
-}
+	core := zapcore.NewTee(cores...)
 
-func getAllLevels() map[string]Level {
-	metadataLevels := levels.All()
+	if o.stacktrace != nil {
+		// The stack trace is captured once for all routes, and only for the entries that are sampled.
+		core = newStacktraceCore(core, o.stacktrace)
+	}
 
-	// Convert to the Level type in this package
-	levels := make(map[string]Level)
-	for module, logLevel := range metadataLevels {
-		levels[module] = logLevel
+	if o.sampling != nil {
+		core = zapcore.NewSamplerWithOptions(core, o.sampling.tick, o.sampling.first, o.sampling.thereafter)
 	}
 
-	return levels
+	return core
 }
 
-type moduleLevelPair struct {
-	module   string
-	logLevel Level
-}
+// nestedErrorOutput is the error output of the entries which are checked by a wrapping core (see writeChecked).
+var nestedErrorOutput zapcore.WriteSyncer = zapcore.Lock(os.Stderr) //nolint:gochecknoglobals
+
+// writeChecked writes an entry which was checked by a wrapping core separately from the checked entry
+// of the logger. The entry is written as updated by the logger (for example, with the caller), and the
+// write errors are reported to stderr in the same way as the write errors of the logger.
+func writeChecked(ce *zapcore.CheckedEntry, entry zapcore.Entry, fields []zapcore.Field) {
+	ce.Entry = entry
+	ce.ErrorOutput = nestedErrorOutput
 
-func newModuleLevels() *moduleLevels {
-	return &moduleLevels{levels: make(map[string]Level)}
+	ce.Write(fields...)
 }
 
-// moduleLevels maintains log levels based on modules.
-type moduleLevels struct {
-	levels  map[string]Level
-	rwmutex sync.RWMutex
+// samplingOptions holds the options for zapcore.NewSamplerWithOptions.
+type samplingOptions struct {
+	tick       time.Duration
+	first      int
+	thereafter int
 }
 
-// Get returns the log level for given module and level.
-func (l *moduleLevels) Get(module string) Level {
-	l.rwmutex.RLock()
-	defer l.rwmutex.RUnlock()
+func newZapEncoder(encoding Encoding, timeEncoder zapcore.TimeEncoder, keys *EncoderKeys) zapcore.Encoder {
+	preset, ok := getEncodingPreset(encoding)
+	if !ok {
+		panic("unsupported encoding " + encoding)
+	}
+
+	cfg := preset.encoderConfig(timeEncoder, keys)
 
-	level, exists := l.levels[module]
-	if !exists {
-		level, exists = l.levels[defaultModuleName]
-		// no configuration exists, default to info
-		if !exists {
-			return defaultLevel
-		}
+	if preset.newEncoder == nil {
+		// The vendor encodings are JSON encodings.
+		return zapcore.NewJSONEncoder(cfg)
 	}
 
-	return level
+	return preset.newEncoder(cfg)
 }
 
-// All returns all set log levels.
-func (l *moduleLevels) All() map[string]Level {
-	l.rwmutex.RLock()
-	levels := l.levels
-	l.rwmutex.RUnlock()
+func isSupportedEncoding(encoding Encoding) bool {
+	_, ok := getEncodingPreset(encoding)
 
-	levelsCopy := make(map[string]Level)
+	return ok
+}
+
+func getOptions(opts []Option) *options {
+	options := defaults.get()
 
-	for module, logLevel := range levels {
-		levelsCopy[module] = logLevel
+	for _, opt := range opts {
+		opt(options)
 	}
 
-	return levelsCopy
+	return options
 }
 
-func (l *moduleLevels) Set(module string, level Level) {
-	l.rwmutex.Lock()
-	l.levels[module] = level
-	l.rwmutex.Unlock()
+// defaultOptions holds the options that are used by loggers that don't explicitly
+// override them. The defaults may be changed using Configure, ConfigureFromEnv or ApplyConfig.
+type defaultOptions struct {
+	options options
+	// opened contains the outputs which were opened by ApplyConfig or ConfigureFromEnv. They are closed when
+	// they are no longer used by the defaults.
+	opened []*openedOutput
+	// generation is incremented when the defaults change so that the cores of existing loggers are rebuilt.
+	generation atomic.Uint64
+	mutex      sync.RWMutex
+}
+
+func newDefaultOptions() *defaultOptions {
+	return &defaultOptions{
+		options: options{
+			stdOut:     os.Stdout,
+			stdErr:     os.Stderr,
+			callerSkip: 1,
+		},
+	}
 }
 
-func (l *moduleLevels) SetDefault(level Level) {
-	l.Set(defaultModuleName, level)
+func (d *defaultOptions) get() *options {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	o := d.options
+
+	if o.encoding == "" {
+		o.encoding = DefaultEncoding
+	}
+
+	return &o
 }
 
-// isEnabled will return true if logging is enabled for given module and level.
-func (l *moduleLevels) isEnabled(module string, level Level) bool {
-	return level >= l.Get(module)
+func (d *defaultOptions) set(update func(o *options)) {
+	d.setOutputs(update, nil)
 }
 
-func newZap(module string, encoding Encoding, stdOut, stdErr zapcore.WriteSyncer) *zap.Logger {
-	encoder := newZapEncoder(encoding)
+// setOutputs updates the defaults, which use the given newly opened outputs. The previously opened outputs
+// which are no longer used are closed after the loggers have been synced.
+func (d *defaultOptions) setOutputs(update func(o *options), opened []*openedOutput) {
+	// Flush the current outputs since they may be replaced.
+	_ = loggers.sync() //nolint:errcheck
 
-	core := zapcore.NewTee(
-		zapcore.NewCore(encoder, zapcore.Lock(stdErr),
-			zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
-				return lvl >= zapcore.ErrorLevel && levels.isEnabled(module, Level(lvl))
-			}),
-		),
-		zapcore.NewCore(encoder, zapcore.Lock(stdOut),
-			zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
-				return lvl < zapcore.ErrorLevel && levels.isEnabled(module, Level(lvl))
-			}),
-		),
-	)
+	d.mutex.Lock()
 
-	return zap.New(core, zap.AddCaller()).Named(module)
-}
+	update(&d.options)
 
-func newZapEncoder(encoding Encoding) zapcore.Encoder {
-	defaultCfg := zapcore.EncoderConfig{
-		TimeKey:        timestampKey,
-		LevelKey:       levelKey,
-		NameKey:        moduleKey,
-		CallerKey:      callerKey,
-		FunctionKey:    zapcore.OmitKey,
-		MessageKey:     messageKey,
-		StacktraceKey:  stacktraceKey,
-		LineEnding:     zapcore.DefaultLineEnding,
-		EncodeLevel:    zapcore.CapitalLevelEncoder,
-		EncodeTime:     zapcore.ISO8601TimeEncoder,
-		EncodeDuration: zapcore.StringDurationEncoder,
-		EncodeCaller:   zapcore.ShortCallerEncoder,
-	}
+	d.generation.Add(1)
 
-	switch strings.ToLower(encoding) {
-	case JSON:
-		cfg := defaultCfg
-		cfg.EncodeLevel = zapcore.LowercaseLevelEncoder
+	var used, unused []*openedOutput
 
-		return zapcore.NewJSONEncoder(cfg)
-	case Console:
-		cfg := defaultCfg
-		cfg.EncodeName = func(moduleName string, encoder zapcore.PrimitiveArrayEncoder) {
-			encoder.AppendString(fmt.Sprintf("[%s]", moduleName))
+	for _, o := range append(d.opened, opened...) {
+		if d.options.uses(o.output) {
+			used = append(used, o)
+		} else {
+			unused = append(unused, o)
 		}
+	}
 
-		return zapcore.NewConsoleEncoder(cfg)
-	default:
-		panic("unsupported encoding " + encoding)
+	d.opened = used
+
+	d.mutex.Unlock()
+
+	// The replaced outputs were synced above and aren't used by the rebuilt cores.
+	closeOutputs(unused)
+}
+
+// openedOutput is an output which was opened from the configuration and the function which closes it.
+type openedOutput struct {
+	output zapcore.WriteSyncer
+	close  func()
+}
+
+func closeOutputs(opened []*openedOutput) {
+	for _, o := range opened {
+		o.close()
 	}
 }
 
-func getOptions(opts []Option) *options {
-	options := &options{
-		encoding:   DefaultEncoding,
-		stdOut:     os.Stdout,
-		stdErr:     os.Stderr,
-		callerSkip: 1,
+// uses returns true if the given output is used by the options. The opened outputs are pointers, so the
+// comparison doesn't panic if an output of the options isn't comparable.
+func (o *options) uses(output zapcore.WriteSyncer) bool {
+	if o.stdOut == output || o.stdErr == output {
+		return true
 	}
 
-	for _, opt := range opts {
-		opt(options)
+	for _, r := range o.routes {
+		if r.output == output {
+			return true
+		}
 	}
 
-	return options
+	return false
 }
@@ -9,10 +9,14 @@ package log
 import (
 	"bytes"
 	"context"
+	"io"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel/sdk/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 type mockWriter struct {
@@ -164,7 +168,7 @@ func TestGetAllLevels(t *testing.T) {
 	sampleModuleWarning := "sample-module-warning"
 	SetLevel(sampleModuleWarning, WARNING)
 
-	allLogLevels := getAllLevels()
+	allLogLevels := levels.all()
 	require.Equal(t, PANIC, allLogLevels[sampleModuleCritical])
 	require.Equal(t, WARNING, allLogLevels[sampleModuleWarning])
 }
@@ -185,6 +189,10 @@ func TestLogLevel(t *testing.T) {
 	verifyLevelsNoError(WARNING, "warn", "warn", "warning", "WARNING")
 	verifyLevelsNoError(DEBUG, "debug", "debug")
 	verifyLevelsNoError(INFO, "info", "INFO")
+	verifyLevelsNoError(TRACE, "trace", "TRACE", "Trace", "-2")
+	verifyLevelsNoError(CRITICAL, "critical", "CRITICAL", "3")
+	verifyLevelsNoError(DEBUG, "Debug", "-1")
+	verifyLevelsNoError(FATAL, "5")
 }
 
 // TestParseLevelError testing 'LogLevel()' used for parsing log levels from strings.
@@ -196,7 +204,7 @@ func TestParseLevelError(t *testing.T) {
 		}
 	}
 
-	verifyLevelError("", "D", "DE BUG", ".")
+	verifyLevelError("", "D", "DE BUG", ".", "-3", "6", "1.5")
 }
 
 func TestParseString(t *testing.T) {
@@ -206,6 +214,8 @@ func TestParseString(t *testing.T) {
 	require.Equal(t, "WARN", WARNING.String())
 	require.Equal(t, "INFO", INFO.String())
 	require.Equal(t, "DEBUG", DEBUG.String())
+	require.Equal(t, "TRACE", TRACE.String())
+	require.Equal(t, "CRITICAL", CRITICAL.String())
 
 	t.Run("unknown log level", func(t *testing.T) {
 		levelInvalid := Level(9999)
@@ -287,13 +297,13 @@ func TestLogSpecGet(t *testing.T) {
 }
 
 func TestLogLevels(t *testing.T) {
-	mlevel := newModuleLevels()
+	mlevel := NewLevelRegistry()
 
-	mlevel.Set("module-xyz-info", INFO)
-	mlevel.Set("module-xyz-debug", DEBUG)
-	mlevel.Set("module-xyz-error", ERROR)
-	mlevel.Set("module-xyz-warning", WARNING)
-	mlevel.Set("module-xyz-panic", PANIC)
+	mlevel.SetLevel("module-xyz-info", INFO)
+	mlevel.SetLevel("module-xyz-debug", DEBUG)
+	mlevel.SetLevel("module-xyz-error", ERROR)
+	mlevel.SetLevel("module-xyz-warning", WARNING)
+	mlevel.SetLevel("module-xyz-panic", PANIC)
 
 	// Run info level checks
 	require.True(t, mlevel.isEnabled("module-xyz-info", PANIC))
@@ -447,3 +457,211 @@ func TestLoggerSkip(t *testing.T) {
 		require.Contains(t, stdErr.Buffer.String(), "log/logger.go:")
 	})
 }
+
+func TestSharedCore(t *testing.T) {
+	const module = "shared-core-module"
+
+	SetLevel(module, INFO)
+
+	stdOut := newMockWriter()
+
+	logger := New(module, WithStdOut(stdOut), WithSpanEvents()).With(zap.String("key", "value"))
+
+	// The context logger shares the core (and therefore the fields) of the logger.
+	require.Same(t, logger.Core(), logger.ctxLogger.Core())
+
+	logger.Info("Sample info log")
+	logger.Infoc(context.Background(), "Sample info log")
+
+	lines := strings.Split(strings.TrimSuffix(stdOut.String(), "\n"), "\n")
+	require.Len(t, lines, 2)
+
+	for _, line := range lines {
+		require.Contains(t, line, `"caller":"log/logger_test.go:`)
+		require.Contains(t, line, `"key":"value"`)
+	}
+}
+
+func TestContextLogDisabledAllocations(t *testing.T) {
+	const module = "disabled-allocations-module"
+
+	SetLevel(module, INFO)
+
+	stdOut := newMockWriter()
+
+	logger := New(module, WithStdOut(stdOut)).With(zap.String("key", "value"))
+
+	ctx, span := trace.NewTracerProvider().Tracer("unit-test").Start(context.Background(), "span")
+	defer span.End()
+
+	allocs := testing.AllocsPerRun(100, func() {
+		logger.Debugc(ctx, "Sample debug log", zap.String("id", "abc"), zap.Int("index", 1))
+	})
+
+	require.Zero(t, allocs)
+	require.Empty(t, stdOut.String())
+
+	logger.Infoc(ctx, "Sample info log", zap.String("id", "abc"))
+	require.Contains(t, stdOut.String(), `"msg":"Sample info log","key":"value","id":"abc","trace_id":"`)
+}
+
+func TestContextLogEnabledAllocations(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocations aren't deterministic with the race detector")
+	}
+
+	const module = "enabled-allocations-module"
+
+	SetLevel(module, INFO)
+
+	logger := New(module, WithRoute(zapcore.AddSync(io.Discard), DEBUG, FATAL)).With(zap.String("key", "value"))
+
+	ctx := context.Background()
+
+	infoAllocs := testing.AllocsPerRun(100, func() {
+		logger.Info("Sample info log", zap.String("id", "abc"), zap.Int("index", 1))
+	})
+
+	// The fields and the tracing field of a context log are allocated at once, so a context log (without
+	// a span, whose IDs are formatted) doesn't allocate more than a log without context.
+	infocAllocs := testing.AllocsPerRun(100, func() {
+		logger.Infoc(ctx, "Sample info log", zap.String("id", "abc"), zap.Int("index", 1))
+	})
+
+	require.Equal(t, infoAllocs, infocAllocs)
+}
+
+func TestDynamicLevel(t *testing.T) {
+	const module = "dynamic-level-module"
+
+	SetLevel(module, INFO)
+
+	ctx, span := trace.NewTracerProvider().Tracer("unit-test").Start(context.Background(), "span")
+	defer span.End()
+
+	levelOf := func(status int) Level {
+		if status >= 500 {
+			return ERROR
+		}
+
+		return DEBUG
+	}
+
+	stdOut := newMockWriter()
+	stdErr := newMockWriter()
+
+	logger := New(module, WithStdOut(stdOut), WithStdErr(stdErr))
+
+	logger.Logc(ctx, levelOf(200), "Request succeeded")
+	logger.Logc(ctx, levelOf(500), "Request failed", zap.Int("status", 500))
+
+	require.Empty(t, stdOut.String())
+	require.Contains(t, stdErr.String(), `"caller":"log/logger_test.go:`)
+	require.Contains(t, stdErr.String(), `"msg":"Request failed","status":500,"trace_id":"`)
+
+	require.Panics(t, func() {
+		logger.Logc(ctx, PANIC, "Sample panic log")
+	})
+
+	stdErr.Reset()
+
+	require.Nil(t, logger.Checkc(ctx, levelOf(200), "Request succeeded"))
+
+	ce := logger.Checkc(ctx, levelOf(500), "Request failed")
+	require.NotNil(t, ce)
+
+	ce.Write(zap.Int("status", 500))
+
+	require.Contains(t, stdErr.String(), `"caller":"log/logger_test.go:`)
+	require.Contains(t, stdErr.String(), `"msg":"Request failed","status":500,"trace_id":"`)
+}
+
+func TestTraceAndCriticalLevels(t *testing.T) {
+	const module = "trace-critical-module"
+
+	require.NoError(t, SetSpec(module+"=trace:INFO"))
+
+	stdOut := newMockWriter()
+	stdErr := newMockWriter()
+
+	logger := New(module, WithStdOut(stdOut), WithStdErr(stdErr))
+
+	require.True(t, logger.IsEnabled(TRACE))
+
+	logger.Tracec(context.Background(), "Sample trace log")
+	logger.Criticalc(context.Background(), "Sample critical log")
+	logger.Logc(context.Background(), CRITICAL, "Sample critical log")
+
+	require.Contains(t, stdOut.String(), `"level":"trace","ts":`)
+	require.Contains(t, stdOut.String(), `"caller":"log/logger_test.go:`)
+	require.Contains(t, stdErr.String(), `"level":"critical","ts":`)
+	require.Len(t, strings.Split(strings.TrimSuffix(stdErr.String(), "\n"), "\n"), 2)
+
+	SetLevel(module, DEBUG)
+
+	stdOut.Reset()
+
+	logger.Tracec(context.Background(), "Sample trace log")
+	require.Empty(t, stdOut.String())
+
+	t.Run("console", func(t *testing.T) {
+		SetLevel(module, TRACE)
+
+		output := newMockWriter()
+
+		logger := New(module, WithRoute(output, TRACE, FATAL), WithEncoding(Console))
+
+		logger.Tracec(context.Background(), "Sample trace log")
+		logger.Criticalc(context.Background(), "Sample critical log")
+
+		require.Contains(t, output.String(), "\tTRACE\t")
+		require.Contains(t, output.String(), "\tCRITICAL\t")
+	})
+}
+
+func TestNamed(t *testing.T) {
+	const module = "named-module"
+
+	entries := logEntries(t, module, JSON, func(l *Log) {
+		SetLevel(module, WARNING)
+		SetLevel(module+".issuer", DEBUG)
+
+		logger := l.With(zap.String("key", "value"))
+
+		require.Same(t, logger, logger.Named(""))
+
+		issuer := logger.Named("issuer")
+		verifier := logger.Named("verifier")
+
+		require.True(t, issuer.IsEnabled(DEBUG))
+		require.False(t, verifier.IsEnabled(INFO))
+		require.True(t, verifier.IsEnabled(WARNING))
+
+		issuer.Debugc(context.Background(), "Issuer debug log")
+		verifier.Info("Verifier info log")
+		verifier.Named("ldp").Warn("Verifier warn log")
+	})
+
+	require.Len(t, entries, 2)
+
+	require.Equal(t, "Issuer debug log", entries[0]["msg"])
+	require.Equal(t, module+".issuer", entries[0]["logger"])
+	require.Equal(t, "value", entries[0]["key"])
+	require.Contains(t, entries[0]["caller"], "log/logger_test.go:")
+
+	require.Equal(t, "Verifier warn log", entries[1]["msg"])
+	require.Equal(t, module+".verifier.ldp", entries[1]["logger"])
+	require.Equal(t, "value", entries[1]["key"])
+
+	require.Equal(t, "child", New("").Named("child").module)
+
+	t.Run("cached", func(t *testing.T) {
+		logger := New(module, WithStdOut(newMockWriter()))
+
+		// The children are shared by the logger and its children created with With.
+		issuer := logger.Named("issuer")
+		require.Same(t, issuer, logger.Named("issuer"))
+		require.Same(t, issuer.registration, logger.With(zap.String("key", "value")).Named("issuer").registration)
+		require.NotSame(t, issuer, logger.Named("verifier"))
+	})
+}
@@ -0,0 +1,170 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package logtest
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/trustbloc/logutil-go/pkg/log"
+)
+
+// Entry is a log entry recorded by an Observer.
+type Entry struct {
+	Level   log.Level
+	Module  string
+	Message string
+	Time    time.Time
+	Caller  string
+	// Fields contains the fields of the entry (including the fields of the logger) as decoded
+	// by a zapcore.MapObjectEncoder. For example, integers are decoded as int64.
+	Fields map[string]interface{}
+}
+
+// Entries is a list of log entries with query helpers. Each filter returns a new list.
+type Entries []Entry
+
+// Observer records the log entries of the loggers created with New.
+type Observer struct {
+	logs *observer.ObservedLogs
+}
+
+// New returns a logger for the given module which records its log entries (at the levels enabled for the
+// module, see log.SetLevel) in the returned Observer. The given options are applied after the observer
+// is set up, so additional routes may be added with log.WithRoute (or WithTestOutput).
+func New(module string, opts ...log.Option) (*log.Log, *Observer) {
+	core, logs := observer.New(zapcore.Level(log.TRACE))
+
+	return log.New(module, append([]log.Option{log.WithCore(core, log.TRACE, log.FATAL)}, opts...)...),
+		&Observer{logs: logs}
+}
+
+// WithTestOutput also writes the log entries to the log of the given test (t.Log), so that the
+// output is only shown for failed tests (or in verbose mode) and is attributed to the test.
+func WithTestOutput(t zaptest.TestingT) log.Option {
+	return log.WithRoute(zaptest.NewTestingWriter(t), log.TRACE, log.FATAL, log.WithRouteEncoding(log.Console))
+}
+
+// Entries returns all recorded entries.
+func (o *Observer) Entries() Entries {
+	return toEntries(o.logs.All())
+}
+
+// TakeAll returns all recorded entries and clears the observer.
+func (o *Observer) TakeAll() Entries {
+	return toEntries(o.logs.TakeAll())
+}
+
+// Len returns the number of recorded entries.
+func (o *Observer) Len() int {
+	return o.logs.Len()
+}
+
+// Level returns the entries with the given level.
+func (e Entries) Level(level log.Level) Entries {
+	return e.Filter(func(entry Entry) bool {
+		return entry.Level == level
+	})
+}
+
+// Module returns the entries of the given module.
+func (e Entries) Module(module string) Entries {
+	return e.Filter(func(entry Entry) bool {
+		return entry.Module == module
+	})
+}
+
+// Message returns the entries with the given message.
+func (e Entries) Message(msg string) Entries {
+	return e.Filter(func(entry Entry) bool {
+		return entry.Message == msg
+	})
+}
+
+// MessageContains returns the entries whose message contains the given snippet.
+func (e Entries) MessageContains(snippet string) Entries {
+	return e.Filter(func(entry Entry) bool {
+		return strings.Contains(entry.Message, snippet)
+	})
+}
+
+// Field returns the entries which have the given field. If the value doesn't have the same type as the
+// decoded field (for example, int instead of int64) then the values are compared using fmt.Sprint.
+func (e Entries) Field(key string, value interface{}) Entries {
+	return e.Filter(func(entry Entry) bool {
+		v, ok := entry.Fields[key]
+		if !ok {
+			return false
+		}
+
+		return reflect.DeepEqual(v, value) || fmt.Sprint(v) == fmt.Sprint(value)
+	})
+}
+
+// HasField returns the entries which have a field with the given key.
+func (e Entries) HasField(key string) Entries {
+	return e.Filter(func(entry Entry) bool {
+		_, ok := entry.Fields[key]
+
+		return ok
+	})
+}
+
+// TraceID returns the entries which were logged with the given trace ID (see log.WithTracing).
+func (e Entries) TraceID(traceID string) Entries {
+	return e.Field(log.FieldTraceID, traceID)
+}
+
+// Filter returns the entries for which keep returns true.
+func (e Entries) Filter(keep func(entry Entry) bool) Entries {
+	var filtered Entries
+
+	for _, entry := range e {
+		if keep(entry) {
+			filtered = append(filtered, entry)
+		}
+	}
+
+	return filtered
+}
+
+// Messages returns the messages of the entries.
+func (e Entries) Messages() []string {
+	messages := make([]string, len(e))
+
+	for i, entry := range e {
+		messages[i] = entry.Message
+	}
+
+	return messages
+}
+
+func toEntries(logs []observer.LoggedEntry) Entries {
+	entries := make(Entries, len(logs))
+
+	for i, l := range logs {
+		entries[i] = Entry{
+			Level:   log.Level(l.Level),
+			Module:  l.LoggerName,
+			Message: l.Message,
+			Time:    l.Time,
+			Fields:  l.ContextMap(),
+		}
+
+		if l.Caller.Defined {
+			entries[i].Caller = l.Caller.TrimmedPath()
+		}
+	}
+
+	return entries
+}
@@ -0,0 +1,68 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package logtest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.uber.org/zap"
+
+	"github.com/trustbloc/logutil-go/pkg/log"
+)
+
+func TestObserver(t *testing.T) {
+	const module = "logtest-module"
+
+	log.SetLevel(module, log.DEBUG)
+
+	logger, observer := New(module, WithTestOutput(t), log.WithFields(zap.String("service", "svc1")))
+
+	ctx, span := trace.NewTracerProvider().Tracer("unit-test").Start(context.Background(), "span")
+	defer span.End()
+
+	logger.Debug("Sample debug log", zap.Int("count", 3))
+	logger.Infoc(ctx, "Sample info log")
+	logger.Error("Sample error log", log.WithError(context.Canceled))
+
+	require.Equal(t, 3, observer.Len())
+
+	entries := observer.Entries()
+
+	require.Equal(t, []string{"Sample debug log", "Sample info log", "Sample error log"}, entries.Messages())
+	require.Len(t, entries.Module(module), 3)
+	require.Empty(t, entries.Module("other"))
+	require.Equal(t, []string{"Sample error log"}, entries.Level(log.ERROR).Messages())
+	require.Len(t, entries.Message("Sample info log"), 1)
+	require.Len(t, entries.MessageContains("Sample"), 3)
+	require.Len(t, entries.Field("count", 3), 1)
+	require.Len(t, entries.Field("count", int64(3)), 1)
+	require.Empty(t, entries.Field("count", 4))
+	require.Len(t, entries.Field("service", "svc1"), 3)
+	require.Len(t, entries.HasField("error"), 1)
+	require.Equal(t, []string{"Sample info log"}, entries.TraceID(span.SpanContext().TraceID().String()).Messages())
+	require.Contains(t, entries[0].Caller, "logtest/logtest_test.go")
+	require.Equal(t, log.DEBUG, entries[0].Level)
+
+	require.Len(t, observer.TakeAll(), 3)
+	require.Zero(t, observer.Len())
+}
+
+func TestObserverModuleLevel(t *testing.T) {
+	const module = "logtest-module-warn"
+
+	log.SetLevel(module, log.WARNING)
+
+	logger, observer := New(module)
+
+	logger.Info("Sample info log")
+	logger.Warn("Sample warn log")
+
+	require.Equal(t, []string{"Sample warn log"}, observer.Entries().Messages())
+}
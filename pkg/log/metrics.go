@@ -0,0 +1,177 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package log
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	meterName = "github.com/trustbloc/logutil-go/pkg/log"
+
+	metricEntries           = "log.entries"
+	metricEntriesSuppressed = "log.entries.suppressed"
+
+	attributeModule = "module"
+	attributeLevel  = "level"
+)
+
+// logMetrics holds the counters for the log entries of a module.
+type logMetrics struct {
+	module     string
+	entries    metric.Int64Counter
+	suppressed metric.Int64Counter
+	attrs      map[zapcore.Level]metric.AddOption
+}
+
+func newLogMetrics(module string, provider metric.MeterProvider) *logMetrics {
+	meter := provider.Meter(meterName)
+
+	entries, err := meter.Int64Counter(metricEntries,
+		metric.WithDescription("The number of log entries written."),
+		metric.WithUnit("{entry}"),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+
+	suppressed, err := meter.Int64Counter(metricEntriesSuppressed,
+		metric.WithDescription("The number of log entries suppressed by the log level."),
+		metric.WithUnit("{entry}"),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+
+	m := &logMetrics{
+		module:     module,
+		entries:    entries,
+		suppressed: suppressed,
+		attrs:      make(map[zapcore.Level]metric.AddOption),
+	}
+
+	for lvl := zapcore.Level(minLogLevel); lvl <= zapcore.Level(maxLogLevel); lvl++ {
+		m.attrs[lvl] = m.newAttributes(lvl)
+	}
+
+	return m
+}
+
+func (m *logMetrics) attributes(lvl zapcore.Level) metric.AddOption {
+	if attrs, ok := m.attrs[lvl]; ok {
+		return attrs
+	}
+
+	return m.newAttributes(lvl)
+}
+
+func (m *logMetrics) newAttributes(lvl zapcore.Level) metric.AddOption {
+	return metric.WithAttributeSet(attribute.NewSet(
+		attribute.String(attributeModule, m.module),
+		attribute.String(attributeLevel, Level(lvl).String()),
+	))
+}
+
+func (m *logMetrics) written(ctx context.Context, lvl zapcore.Level) {
+	m.entries.Add(ctx, 1, m.attributes(lvl))
+}
+
+func (m *logMetrics) dropped(lvl zapcore.Level) {
+	m.suppressed.Add(context.Background(), 1, m.attributes(lvl))
+}
+
+// metricsCore wraps a core and counts the entries that are written as well as the entries
+// that are suppressed because their level is not enabled.
+type metricsCore struct {
+	zapcore.Core
+	metrics *logMetrics
+}
+
+func newMetricsCore(core zapcore.Core, metrics *logMetrics) zapcore.Core {
+	return &metricsCore{Core: core, metrics: metrics}
+}
+
+// Enabled is called by the zap logger (for levels below DPANIC) before an entry is checked.
+func (c *metricsCore) Enabled(lvl zapcore.Level) bool {
+	if c.Core.Enabled(lvl) {
+		return true
+	}
+
+	c.metrics.dropped(lvl)
+
+	return false
+}
+
+// Level returns the minimum enabled level of the wrapped core. It is implemented so that
+// zapcore.LevelOf doesn't probe (and count) each level using Enabled.
+func (c *metricsCore) Level() zapcore.Level {
+	return zapcore.LevelOf(c.Core)
+}
+
+func (c *metricsCore) With(fields []zapcore.Field) zapcore.Core {
+	return &metricsCore{Core: c.Core.With(fields), metrics: c.metrics}
+}
+
+func (c *metricsCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Core.Enabled(entry.Level) {
+		// The zap logger doesn't call Enabled for DPANIC and above so count the suppressed entry here.
+		if entry.Level >= zapcore.DPanicLevel {
+			c.metrics.dropped(entry.Level)
+		}
+
+		return ce
+	}
+
+	// The entry is checked separately so that only the entries which are written by the wrapped core (for
+	// example, the entries which aren't dropped by the sampler) are counted.
+	checked := c.Core.Check(entry, nil)
+	if checked == nil {
+		return ce
+	}
+
+	return ce.AddCore(entry, &metricsWriter{metrics: c.metrics, checked: checked})
+}
+
+// metricsWriter writes the entry to the cores of the wrapped core that accepted it and counts the entry.
+type metricsWriter struct {
+	metrics *logMetrics
+	checked *zapcore.CheckedEntry
+}
+
+func (w *metricsWriter) Enabled(zapcore.Level) bool {
+	return true
+}
+
+func (w *metricsWriter) With([]zapcore.Field) zapcore.Core {
+	return w
+}
+
+func (w *metricsWriter) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return ce.AddCore(entry, w)
+}
+
+func (w *metricsWriter) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	ctx := contextFromFields(fields)
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	w.metrics.written(ctx, entry.Level)
+
+	writeChecked(w.checked, entry, fields)
+
+	return nil
+}
+
+func (w *metricsWriter) Sync() error {
+	return nil
+}
@@ -0,0 +1,160 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package log
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestMetrics(t *testing.T) {
+	const module = "metrics-module"
+
+	SetLevel(module, WARNING)
+
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	logger := New(module, WithStdOut(newMockWriter()), WithStdErr(newMockWriter()), WithMetrics(provider))
+
+	logger.Debug("Sample debug log")
+	logger.Info("Sample info log")
+	logger.Infoc(context.Background(), "Sample info log")
+	logger.Warn("Sample warn log")
+	logger.Warnc(context.Background(), "Sample warn log")
+	logger.Error("Sample error log")
+	logger.With(WithID("id1")).Errorc(context.Background(), "Sample error log")
+
+	require.Panics(t, func() {
+		logger.Panic("Sample panic log")
+	})
+
+	rm := &metricdata.ResourceMetrics{}
+	require.NoError(t, reader.Collect(context.Background(), rm))
+
+	entries := counterValues(t, rm, metricEntries)
+	require.Len(t, entries, 3)
+	require.Equal(t, int64(2), entries[WARNING.String()])
+	require.Equal(t, int64(2), entries[ERROR.String()])
+	require.Equal(t, int64(1), entries[PANIC.String()])
+
+	suppressed := counterValues(t, rm, metricEntriesSuppressed)
+	require.Len(t, suppressed, 2)
+	require.Equal(t, int64(1), suppressed[DEBUG.String()])
+	require.Equal(t, int64(2), suppressed[INFO.String()])
+
+	t.Run("suppressed panic", func(t *testing.T) {
+		SetLevel(module, FATAL)
+		defer SetLevel(module, WARNING)
+
+		require.Panics(t, func() {
+			logger.Panic("Sample panic log")
+		})
+
+		rm := &metricdata.ResourceMetrics{}
+		require.NoError(t, reader.Collect(context.Background(), rm))
+
+		require.Equal(t, int64(1), counterValues(t, rm, metricEntriesSuppressed)[PANIC.String()])
+		require.Equal(t, int64(1), counterValues(t, rm, metricEntries)[PANIC.String()])
+	})
+}
+
+func TestMetricsWithFlightRecorder(t *testing.T) {
+	const module = "metrics-flight-recorder-module"
+
+	SetLevel(module, INFO)
+
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	stdOut := newMockWriter()
+
+	logger := New(module, WithStdOut(stdOut), WithStdErr(newMockWriter()), WithMetrics(provider), WithFlightRecorder(10))
+
+	for i := 0; i < 5; i++ {
+		logger.Debug("Sample debug log")
+	}
+
+	rm := &metricdata.ResourceMetrics{}
+	require.NoError(t, reader.Collect(context.Background(), rm))
+
+	// The buffered entries aren't written.
+	require.Empty(t, counterValues(t, rm, metricEntries))
+	require.Equal(t, map[string]int64{DEBUG.String(): 5}, counterValues(t, rm, metricEntriesSuppressed))
+
+	logger.Error("Sample error log")
+	require.Contains(t, stdOut.String(), "Sample debug log")
+
+	rm = &metricdata.ResourceMetrics{}
+	require.NoError(t, reader.Collect(context.Background(), rm))
+
+	// The flushed entries are written.
+	require.Equal(t, map[string]int64{DEBUG.String(): 5, ERROR.String(): 1}, counterValues(t, rm, metricEntries))
+}
+
+func TestMetricsWithSampling(t *testing.T) {
+	const module = "metrics-sampling-module"
+
+	resetDefaults(t)
+
+	require.NoError(t, ApplyConfig(&Config{Sampling: &SamplingConfig{Tick: "1m", First: 1}}))
+
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	stdOut := newMockWriter()
+
+	logger := New(module, WithStdOut(stdOut), WithMetrics(provider))
+
+	for i := 0; i < 5; i++ {
+		logger.Info("Sample info log")
+	}
+
+	require.Equal(t, 1, countLines(stdOut.String(), "Sample info log"))
+
+	rm := &metricdata.ResourceMetrics{}
+	require.NoError(t, reader.Collect(context.Background(), rm))
+
+	// The entries which are dropped by the sampler aren't counted.
+	require.Equal(t, map[string]int64{INFO.String(): 1}, counterValues(t, rm, metricEntries))
+}
+
+// counterValues returns the values of the given counter for the test module, keyed by level.
+func counterValues(t *testing.T, rm *metricdata.ResourceMetrics, name string) map[string]int64 {
+	t.Helper()
+
+	values := make(map[string]int64)
+
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != name {
+				continue
+			}
+
+			sum, ok := m.Data.(metricdata.Sum[int64])
+			require.True(t, ok)
+
+			for _, dp := range sum.DataPoints {
+				module, ok := dp.Attributes.Value(attributeModule)
+				require.True(t, ok)
+				require.Equal(t, attribute.STRING, module.Type())
+
+				level, ok := dp.Attributes.Value(attributeLevel)
+				require.True(t, ok)
+
+				values[level.AsString()] = dp.Value
+			}
+		}
+	}
+
+	return values
+}
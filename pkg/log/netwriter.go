@@ -0,0 +1,149 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package log
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	networkDialTimeout  = 5 * time.Second
+	networkWriteTimeout = 5 * time.Second
+	// The interval after which the connection is re-established is doubled after each failed attempt.
+	networkMinRetryInterval = 1 * time.Second
+	networkMaxRetryInterval = 30 * time.Second
+)
+
+var (
+	errNetworkUnavailable = errors.New("connection unavailable")
+	errNetworkClosed      = errors.New("writer closed")
+)
+
+// networkWriter is a zapcore.WriteSyncer that writes log entries to a TCP or UDP connection.
+// If a write fails (or doesn't complete within the write timeout, for example because the collector
+// is stalled) then the connection is closed. Entries are dropped until the retry interval has passed,
+// so that the logger isn't blocked by dialing an unreachable collector, and a new connection is then
+// established on the next write.
+type networkWriter struct {
+	network       string
+	address       string
+	writeTimeout  time.Duration
+	conn          net.Conn
+	retryAt       time.Time
+	retryInterval time.Duration
+	lastErr       error
+	closed        bool
+	mutex         sync.Mutex
+}
+
+func newNetworkWriter(network, address string) (*networkWriter, error) {
+	if network == "" {
+		network = "tcp"
+	}
+
+	w := &networkWriter{
+		network:      network,
+		address:      address,
+		writeTimeout: networkWriteTimeout,
+	}
+
+	conn, err := w.dial()
+	if err != nil {
+		return nil, err
+	}
+
+	w.conn = conn
+
+	return w, nil
+}
+
+func (w *networkWriter) Write(p []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.closed {
+		return 0, fmt.Errorf("write to %s %s: %w", w.network, w.address, errNetworkClosed)
+	}
+
+	if w.conn == nil {
+		if time.Now().Before(w.retryAt) {
+			return 0, fmt.Errorf("write to %s %s: %w: %w", w.network, w.address, errNetworkUnavailable, w.lastErr)
+		}
+
+		conn, err := w.dial()
+		if err != nil {
+			w.fail(err)
+
+			return 0, err
+		}
+
+		w.conn = conn
+		w.retryInterval = 0
+	}
+
+	// The deadline prevents a stalled connection from blocking the logger (which holds the lock of the output).
+	err := w.conn.SetWriteDeadline(time.Now().Add(w.writeTimeout))
+
+	n := 0
+	if err == nil {
+		n, err = w.conn.Write(p)
+	}
+
+	if err != nil {
+		_ = w.conn.Close() //nolint:errcheck
+
+		w.conn = nil
+
+		w.fail(err)
+
+		return n, fmt.Errorf("write to %s %s: %w", w.network, w.address, err)
+	}
+
+	return n, nil
+}
+
+// fail sets the time after which the connection is re-established.
+func (w *networkWriter) fail(err error) {
+	w.retryInterval = min(max(2*w.retryInterval, networkMinRetryInterval), networkMaxRetryInterval)
+	w.retryAt = time.Now().Add(w.retryInterval)
+	w.lastErr = err
+}
+
+func (w *networkWriter) Sync() error {
+	return nil
+}
+
+// Close closes the connection. The connection isn't re-established after the writer is closed.
+func (w *networkWriter) Close() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.closed = true
+
+	if w.conn == nil {
+		return nil
+	}
+
+	err := w.conn.Close()
+
+	w.conn = nil
+
+	return err
+}
+
+func (w *networkWriter) dial() (net.Conn, error) {
+	conn, err := net.DialTimeout(w.network, w.address, networkDialTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("dial %s %s: %w", w.network, w.address, err)
+	}
+
+	return conn, nil
+}
@@ -0,0 +1,120 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package log
+
+import (
+	"bufio"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNetworkWriterStalled(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, listener.Close())
+	}()
+
+	// The collector accepts the connection but never reads from it.
+	accepted := make(chan net.Conn, 1)
+
+	go func() {
+		conn, err := listener.Accept()
+		if err == nil {
+			accepted <- conn
+		}
+	}()
+
+	w, err := newNetworkWriter("tcp", listener.Addr().String())
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, w.Close())
+	}()
+
+	w.writeTimeout = 100 * time.Millisecond
+
+	conn := <-accepted
+	defer conn.Close() //nolint:errcheck
+
+	// The payload is larger than the socket buffers, so the write stalls until the deadline.
+	payload := make([]byte, 64<<20)
+
+	start := time.Now()
+
+	_, err = w.Write(payload)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "i/o timeout")
+	require.Less(t, time.Since(start), 10*time.Second)
+
+	// The connection is re-established on the next write.
+	require.Nil(t, w.conn)
+}
+
+func TestNetworkWriterRetry(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	w, err := newNetworkWriter("tcp", listener.Addr().String())
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, w.Close())
+	}()
+
+	// The collector becomes unreachable.
+	require.NoError(t, listener.Close())
+	require.NoError(t, w.conn.Close())
+
+	w.conn = nil
+
+	_, err = w.Write([]byte("entry 1\n"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "dial tcp")
+
+	// The collector is reachable again (at another address so that the test doesn't depend on port reuse).
+	listener, err = net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, listener.Close())
+	}()
+
+	w.address = listener.Addr().String()
+
+	// The entry is dropped without dialing until the retry interval has passed.
+	_, err = w.Write([]byte("entry 2\n"))
+	require.ErrorIs(t, err, errNetworkUnavailable)
+	require.Nil(t, w.conn)
+	require.Equal(t, networkMinRetryInterval, w.retryInterval)
+
+	w.retryAt = time.Now()
+
+	_, err = w.Write([]byte("entry 3\n"))
+	require.NoError(t, err)
+
+	conn, err := listener.Accept()
+	require.NoError(t, err)
+
+	defer conn.Close() //nolint:errcheck
+
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	require.NoError(t, err)
+	require.Equal(t, "entry 3\n", line)
+	require.Zero(t, w.retryInterval)
+
+	require.NoError(t, w.Close())
+
+	// The connection isn't re-established after the writer is closed.
+	_, err = w.Write([]byte("entry 4\n"))
+	require.ErrorIs(t, err, errNetworkClosed)
+	require.Nil(t, w.conn)
+}
@@ -0,0 +1,12 @@
+//go:build !race
+
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package log
+
+// raceEnabled is true if the tests are run with the race detector (see race_test.go).
+const raceEnabled = false
@@ -0,0 +1,300 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package log
+
+import (
+	"os"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/mattn/go-colorable"
+	"github.com/mattn/go-isatty"
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// EnvNoColor disables colors in the pretty encoding if set to a non-empty value (see https://no-color.org).
+const EnvNoColor = "NO_COLOR"
+
+const (
+	prettyTimeLayout   = "15:04:05.000"
+	prettyLevelWidth   = 5
+	prettyModuleWidth  = 16
+	prettyMessageWidth = 40
+	prettyIDLength     = 8
+)
+
+const (
+	colorReset   = "\x1b[0m"
+	colorDim     = "\x1b[2m"
+	colorRed     = "\x1b[31m"
+	colorYellow  = "\x1b[33m"
+	colorBlue    = "\x1b[34m"
+	colorMagenta = "\x1b[35m"
+)
+
+// prettyEncoder is a human-friendly encoder for local development. The time, level, module and message are
+// output in aligned columns followed by the fields (as key=value pairs) and the caller. Trace, span and
+// correlation IDs are shortened. Levels are colored and the time and caller are dimmed if colors are enabled.
+type prettyEncoder struct {
+	*logfmtEncoder
+	color bool
+}
+
+func newPrettyEncoder(cfg zapcore.EncoderConfig) zapcore.Encoder {
+	return &prettyEncoder{
+		logfmtEncoder: &logfmtEncoder{
+			EncoderConfig: &cfg,
+			buf:           logfmtBufferPool.Get(),
+		},
+	}
+}
+
+// AddString shortens the values of the tracing and correlation ID fields.
+func (e *prettyEncoder) AddString(key, value string) {
+	if e.prefix == "" && isPrettyIDKey(key) && len(value) > prettyIDLength {
+		value = value[:prettyIDLength]
+	}
+
+	e.logfmtEncoder.AddString(key, value)
+}
+
+func (e *prettyEncoder) Clone() zapcore.Encoder {
+	return &prettyEncoder{
+		logfmtEncoder: e.logfmtEncoder.Clone().(*logfmtEncoder), //nolint:forcetypeassert
+		color:         e.color,
+	}
+}
+
+//nolint:cyclop
+func (e *prettyEncoder) EncodeEntry(entry zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	fieldsEncoder := e.Clone().(*prettyEncoder) //nolint:forcetypeassert
+	defer fieldsEncoder.buf.Free()
+
+	for i := range fields {
+		fields[i].AddTo(fieldsEncoder)
+	}
+
+	line := logfmtBufferPool.Get()
+
+	if e.TimeKey != "" && e.EncodeTime != nil {
+		enc := &prettyArrayEncoder{buf: logfmtBufferPool.Get()}
+		e.EncodeTime(entry.Time, enc)
+
+		e.appendStyled(line, colorDim, enc.buf.String())
+		line.AppendByte(' ')
+
+		enc.buf.Free()
+	}
+
+	if e.LevelKey != "" {
+		e.appendStyled(line, prettyLevelColor(entry.Level), pad(Level(entry.Level).String(), prettyLevelWidth))
+		line.AppendByte(' ')
+	}
+
+	if e.NameKey != "" && entry.LoggerName != "" {
+		line.AppendString(pad("["+entry.LoggerName+"]", prettyModuleWidth))
+		line.AppendByte(' ')
+	}
+
+	if e.MessageKey != "" {
+		if fieldsEncoder.buf.Len() > 0 {
+			line.AppendString(pad(entry.Message, prettyMessageWidth))
+		} else {
+			line.AppendString(entry.Message)
+		}
+	}
+
+	if fieldsEncoder.buf.Len() > 0 {
+		line.AppendByte(' ')
+		_, _ = line.Write(fieldsEncoder.buf.Bytes()) //nolint:errcheck
+	}
+
+	if e.CallerKey != "" && entry.Caller.Defined {
+		line.AppendByte(' ')
+		e.appendStyled(line, colorDim, entry.Caller.TrimmedPath())
+	}
+
+	lineEnding := e.LineEnding
+	if lineEnding == "" {
+		lineEnding = zapcore.DefaultLineEnding
+	}
+
+	line.AppendString(lineEnding)
+
+	if e.StacktraceKey != "" && entry.Stack != "" {
+		line.AppendString(entry.Stack)
+		line.AppendString(lineEnding)
+	}
+
+	return line, nil
+}
+
+func (e *prettyEncoder) appendStyled(buf *buffer.Buffer, color, value string) {
+	if !e.color {
+		buf.AppendString(value)
+
+		return
+	}
+
+	buf.AppendString(color)
+	buf.AppendString(value)
+	buf.AppendString(colorReset)
+}
+
+func prettyLevelColor(level zapcore.Level) string {
+	switch {
+	case level <= zapcore.DebugLevel:
+		return colorMagenta
+	case level == zapcore.InfoLevel:
+		return colorBlue
+	case level == zapcore.WarnLevel:
+		return colorYellow
+	default:
+		return colorRed
+	}
+}
+
+func isPrettyIDKey(key string) bool {
+	switch key {
+	case FieldTraceID, FieldSpanID, FieldParentSpanID, FieldCorrelationID:
+		return true
+	default:
+		return false
+	}
+}
+
+// pad pads the given value with spaces to the given width.
+func pad(value string, width int) string {
+	n := utf8.RuneCountInString(value)
+	if n >= width {
+		return value
+	}
+
+	b := make([]byte, 0, len(value)+width-n)
+	b = append(b, value...)
+
+	for ; n < width; n++ {
+		b = append(b, ' ')
+	}
+
+	return string(b)
+}
+
+// withColor enables colors if the given encoder is a pretty encoder, colors are not disabled by
+// the NO_COLOR environment variable and the output is a terminal. On Windows, the output is
+// wrapped so that the ANSI color sequences are translated.
+func withColor(encoder zapcore.Encoder, output zapcore.WriteSyncer) (zapcore.Encoder, zapcore.WriteSyncer) {
+	pretty, ok := encoder.(*prettyEncoder)
+	if !ok || os.Getenv(EnvNoColor) != "" {
+		return encoder, output
+	}
+
+	f, ok := output.(*os.File)
+	if !ok || !(isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())) {
+		return encoder, output
+	}
+
+	colored := pretty.Clone().(*prettyEncoder) //nolint:forcetypeassert
+	colored.color = true
+
+	w := colorable.NewColorable(f)
+	if ws, ok := w.(zapcore.WriteSyncer); ok {
+		return colored, ws
+	}
+
+	return colored, zapcore.AddSync(w)
+}
+
+// prettyArrayEncoder appends the values to a buffer, separated by a space.
+type prettyArrayEncoder struct {
+	buf *buffer.Buffer
+}
+
+func (a *prettyArrayEncoder) separate() {
+	if a.buf.Len() > 0 {
+		a.buf.AppendByte(' ')
+	}
+}
+
+func (a *prettyArrayEncoder) AppendBool(value bool) {
+	a.separate()
+	a.buf.AppendBool(value)
+}
+
+func (a *prettyArrayEncoder) AppendByteString(value []byte) {
+	a.AppendString(string(value))
+}
+
+func (a *prettyArrayEncoder) AppendComplex128(value complex128) {
+	a.AppendString(strconv.FormatComplex(value, 'g', -1, 128))
+}
+
+func (a *prettyArrayEncoder) AppendComplex64(value complex64) {
+	a.AppendString(strconv.FormatComplex(complex128(value), 'g', -1, 64))
+}
+
+func (a *prettyArrayEncoder) AppendFloat64(value float64) {
+	a.separate()
+	a.buf.AppendFloat(value, 64)
+}
+
+func (a *prettyArrayEncoder) AppendFloat32(value float32) {
+	a.separate()
+	a.buf.AppendFloat(float64(value), 32)
+}
+
+func (a *prettyArrayEncoder) AppendInt(value int) {
+	a.AppendInt64(int64(value))
+}
+
+func (a *prettyArrayEncoder) AppendInt64(value int64) {
+	a.separate()
+	a.buf.AppendInt(value)
+}
+
+func (a *prettyArrayEncoder) AppendInt32(value int32) {
+	a.AppendInt64(int64(value))
+}
+
+func (a *prettyArrayEncoder) AppendInt16(value int16) {
+	a.AppendInt64(int64(value))
+}
+
+func (a *prettyArrayEncoder) AppendInt8(value int8) {
+	a.AppendInt64(int64(value))
+}
+
+func (a *prettyArrayEncoder) AppendString(value string) {
+	a.separate()
+	a.buf.AppendString(value)
+}
+
+func (a *prettyArrayEncoder) AppendUint(value uint) {
+	a.AppendUint64(uint64(value))
+}
+
+func (a *prettyArrayEncoder) AppendUint64(value uint64) {
+	a.separate()
+	a.buf.AppendUint(value)
+}
+
+func (a *prettyArrayEncoder) AppendUint32(value uint32) {
+	a.AppendUint64(uint64(value))
+}
+
+func (a *prettyArrayEncoder) AppendUint16(value uint16) {
+	a.AppendUint64(uint64(value))
+}
+
+func (a *prettyArrayEncoder) AppendUint8(value uint8) {
+	a.AppendUint64(uint64(value))
+}
+
+func (a *prettyArrayEncoder) AppendUintptr(value uintptr) {
+	a.AppendUint64(uint64(value))
+}
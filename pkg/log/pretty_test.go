@@ -0,0 +1,97 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package log
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestPrettyEncoding(t *testing.T) {
+	const module = "pretty-module"
+
+	SetLevel(module, DEBUG)
+
+	t.Run("no color", func(t *testing.T) {
+		output := newMockWriter()
+
+		logger := New(module, WithEncoding(Pretty), WithRoute(output, DEBUG, FATAL))
+
+		ctx, span := trace.NewTracerProvider().Tracer("unit-test").Start(context.Background(), "span")
+		defer span.End()
+
+		logger.Warnc(ctx, "Sample warn log", WithCorrelationID("0123456789abcdef"), zap.Int("count", 3))
+		logger.Info("No fields")
+
+		lines := strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n")
+		require.Len(t, lines, 2)
+
+		require.NotContains(t, output.String(), "\x1b[")
+
+		traceID := span.SpanContext().TraceID().String()[:prettyIDLength]
+		spanID := span.SpanContext().SpanID().String()[:prettyIDLength]
+
+		require.Regexp(t, `^\d\d:\d\d:\d\d\.\d{3} WARN  \[pretty-module\]  Sample warn log {25} `+
+			`correlation_id=01234567 count=3 trace_id=`+traceID+` span_id=`+spanID+` log/pretty_test.go:\d+$`, lines[0])
+		require.Regexp(t, `^\d\d:\d\d:\d\d\.\d{3} INFO  \[pretty-module\]  No fields log/pretty_test.go:\d+$`, lines[1])
+	})
+
+	t.Run("color", func(t *testing.T) {
+		encoder := newZapEncoder(Pretty, nil, &EncoderKeys{Caller: OmitKey}).(*prettyEncoder) //nolint:forcetypeassert
+		encoder.color = true
+
+		buf, err := encoder.EncodeEntry(zapcore.Entry{
+			Level:      zapcore.ErrorLevel,
+			Time:       time.Date(2024, 1, 2, 3, 4, 5, 6000000, time.UTC),
+			LoggerName: module,
+			Message:    "Sample error log",
+			Stack:      "stack",
+		}, nil)
+		require.NoError(t, err)
+
+		require.Equal(t, colorDim+"03:04:05.006"+colorReset+" "+colorRed+"ERROR"+colorReset+
+			" [pretty-module]  Sample error log\nstack\n", buf.String())
+	})
+
+	t.Run("NO_COLOR", func(t *testing.T) {
+		t.Setenv(EnvNoColor, "1")
+
+		encoder := newZapEncoder(Pretty, nil, nil)
+
+		colored, output := withColor(encoder, os.Stdout)
+		require.Same(t, encoder, colored)
+		require.Equal(t, os.Stdout, output)
+	})
+
+	t.Run("not a terminal", func(t *testing.T) {
+		encoder := newZapEncoder(Pretty, nil, nil)
+		output := zapcore.AddSync(newMockWriter())
+
+		colored, o := withColor(encoder, output)
+		require.Same(t, encoder, colored)
+		require.Equal(t, output, o)
+
+		jsonEncoder := newZapEncoder(JSON, nil, nil)
+
+		colored, _ = withColor(jsonEncoder, os.Stdout)
+		require.Equal(t, jsonEncoder, colored)
+	})
+}
+
+func TestPad(t *testing.T) {
+	require.Equal(t, "ab  ", pad("ab", 4))
+	require.Equal(t, "héé ", pad("héé", 4))
+	require.Equal(t, "abcde", pad("abcde", 4))
+}
@@ -0,0 +1,13 @@
+//go:build race
+
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package log
+
+// raceEnabled is true if the tests are run with the race detector, which changes the number of allocations
+// (for example, sync.Pool randomly drops items).
+const raceEnabled = true
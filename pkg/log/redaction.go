@@ -0,0 +1,59 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package log
+
+import (
+	"regexp"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const defaultRedactionReplacement = "[REDACTED]"
+
+// redactionRule redacts the values of log fields. If only a field is specified then the entire value of the
+// field is replaced. If a pattern is specified then the matching parts of string values are replaced (in the
+// given field or, if no field is specified, in all string fields).
+type redactionRule struct {
+	field       string
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+func (r *redactionRule) apply(field zapcore.Field) (zapcore.Field, bool) {
+	if r.field != "" && r.field != field.Key {
+		return field, false
+	}
+
+	if r.pattern == nil {
+		return zap.String(field.Key, r.replacement), true
+	}
+
+	if field.Type != zapcore.StringType || !r.pattern.MatchString(field.String) {
+		return field, false
+	}
+
+	return zap.String(field.Key, r.pattern.ReplaceAllString(field.String, r.replacement)), true
+}
+
+// newRedactionCore wraps the given core so that the redaction rules are applied to the fields before they are written.
+func newRedactionCore(core zapcore.Core, rules []*redactionRule) zapcore.Core {
+	return newTransformCore(core, func(fields []zapcore.Field) []zapcore.Field {
+		return replaceFields(fields, func(field zapcore.Field) (zapcore.Field, bool) {
+			redacted := false
+
+			for _, rule := range rules {
+				if f, ok := rule.apply(field); ok {
+					field = f
+					redacted = true
+				}
+			}
+
+			return field, redacted
+		})
+	})
+}
@@ -0,0 +1,46 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package log
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestRedactionCore(t *testing.T) {
+	rules := []*redactionRule{
+		{field: FieldToken, replacement: defaultRedactionReplacement},
+		{pattern: regexp.MustCompile(`\d{4}-\d{4}`), replacement: "####"},
+		{field: FieldURL, pattern: regexp.MustCompile(`key=[^&]+`), replacement: "key=***"},
+	}
+
+	stdOut := newMockWriter()
+
+	logger := New("redaction-module", WithStdOut(stdOut), WithEncoding(JSON),
+		func(o *options) { o.redactionRules = rules },
+	).With(WithToken("token1"))
+
+	fields := []zap.Field{
+		WithName("card 1234-5678"),
+		WithURL("https://example.com?key=abc&id=1"),
+		WithHTTPStatus(200),
+	}
+
+	logger.Info("Sample info log", fields...)
+
+	require.Contains(t, stdOut.String(), `"token":"[REDACTED]"`)
+	require.Contains(t, stdOut.String(), `"name":"card ####"`)
+	require.Contains(t, stdOut.String(), `"url":"https://example.com?key=***&id=1"`)
+	require.Contains(t, stdOut.String(), `"httpStatus":200`)
+
+	// The fields provided by the caller must not be modified.
+	require.Equal(t, "card 1234-5678", fields[0].String)
+	require.Equal(t, "https://example.com?key=abc&id=1", fields[1].String)
+}
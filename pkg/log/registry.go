@@ -0,0 +1,174 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package log
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sort"
+	"sync"
+	"syscall"
+
+	"go.uber.org/zap/zapcore"
+)
+
+//nolint:gochecknoglobals
+var loggers = newLoggerRegistry()
+
+// registration is shared by a logger and its children (see Log.With). The logger is removed from the
+// registry once the registration is no longer reachable, i.e. when the logger and all of its children
+// have been garbage collected.
+type registration struct {
+	id uint64
+}
+
+type registeredLogger struct {
+	module string
+	core   zapcore.Core
+}
+
+// loggerRegistry keeps track of the live loggers so that their outputs may be synced at once,
+// as well as of the names of all modules for which a logger was created.
+type loggerRegistry struct {
+	loggers map[uint64]*registeredLogger
+	modules map[string]struct{}
+	nextID  uint64
+	mutex   sync.Mutex
+}
+
+func newLoggerRegistry() *loggerRegistry {
+	return &loggerRegistry{
+		loggers: make(map[uint64]*registeredLogger),
+		modules: make(map[string]struct{}),
+	}
+}
+
+func (r *loggerRegistry) register(module string, core zapcore.Core) *registration {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.nextID++
+
+	reg := &registration{id: r.nextID}
+
+	r.loggers[reg.id] = &registeredLogger{module: module, core: core}
+	r.modules[module] = struct{}{}
+
+	runtime.AddCleanup(reg, r.remove, reg.id)
+
+	return reg
+}
+
+func (r *loggerRegistry) remove(id uint64) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	delete(r.loggers, id)
+}
+
+func (r *loggerRegistry) cores() []zapcore.Core {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	cores := make([]zapcore.Core, 0, len(r.loggers))
+
+	for _, l := range r.loggers {
+		cores = append(cores, l.core)
+	}
+
+	return cores
+}
+
+func (r *loggerRegistry) sync() error {
+	var errs []error
+
+	for _, core := range r.cores() {
+		if err := core.Sync(); err != nil && !isUnsyncableError(err) {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (r *loggerRegistry) moduleNames() []string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	modules := make([]string, 0, len(r.modules))
+
+	for module := range r.modules {
+		modules = append(modules, module)
+	}
+
+	return modules
+}
+
+// isUnsyncableError returns true if the error was returned because the output doesn't support syncing,
+// which is the case for stdout and stderr if they are a terminal or a pipe.
+func isUnsyncableError(err error) bool {
+	return errors.Is(err, syscall.EINVAL) || errors.Is(err, syscall.ENOTTY)
+}
+
+// Sync flushes the outputs of all live loggers. Errors which are returned because an output doesn't
+// support syncing (for example, stdout if it is a terminal) are ignored.
+//
+// Sync should be called before the process exits, for example:
+//
+//	defer log.Sync()
+func Sync() error {
+	return loggers.sync()
+}
+
+// Shutdown flushes the outputs of all live loggers in the same way as Sync. If the context is done
+// before the outputs are flushed then the context error is returned.
+func Shutdown(ctx context.Context) error {
+	done := make(chan error, 1)
+
+	go func() {
+		done <- loggers.sync()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// GetModules returns the names of all known modules, sorted by name. These are the modules for which a logger
+// was created as well as the modules for which a log level was set, including modules which are still at the
+// default level (and which are therefore not included in the spec returned by GetSpec).
+func GetModules() []string {
+	known := make(map[string]struct{})
+
+	for _, module := range loggers.moduleNames() {
+		known[module] = struct{}{}
+	}
+
+	for module := range levels.all() {
+		known[module] = struct{}{}
+	}
+
+	for _, override := range GetLevelOverrides() {
+		known[override.Module] = struct{}{}
+	}
+
+	delete(known, defaultModuleName)
+
+	modules := make([]string, 0, len(known))
+
+	for module := range known {
+		modules = append(modules, module)
+	}
+
+	sort.Strings(modules)
+
+	return modules
+}
@@ -0,0 +1,110 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package log
+
+import (
+	"context"
+	"errors"
+	"os"
+	"runtime"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type syncWriter struct {
+	*mockWriter
+	syncs   atomic.Int32
+	err     error
+	release chan struct{}
+}
+
+func (w *syncWriter) Sync() error {
+	if w.release != nil {
+		<-w.release
+	}
+
+	w.syncs.Add(1)
+
+	return w.err
+}
+
+func TestSync(t *testing.T) {
+	const module = "registry-module"
+
+	t.Run("sync", func(t *testing.T) {
+		output := &syncWriter{mockWriter: newMockWriter()}
+
+		logger := New(module, WithRoute(output, DEBUG, FATAL)).With(zap.String("key", "value"))
+
+		require.NoError(t, Sync())
+		require.Equal(t, int32(1), output.syncs.Load())
+
+		output.err = errors.New("sync failed")
+
+		require.ErrorContains(t, Sync(), "sync failed")
+
+		output.err = &os.PathError{Op: "sync", Path: "/dev/stdout", Err: syscall.EINVAL}
+
+		require.NoError(t, Sync())
+
+		runtime.KeepAlive(logger)
+	})
+
+	t.Run("shutdown", func(t *testing.T) {
+		output := &syncWriter{mockWriter: newMockWriter(), release: make(chan struct{})}
+
+		logger := New(module, WithRoute(output, DEBUG, FATAL))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		require.ErrorIs(t, Shutdown(ctx), context.DeadlineExceeded)
+
+		close(output.release)
+
+		require.NoError(t, Shutdown(context.Background()))
+
+		runtime.KeepAlive(logger)
+	})
+
+	t.Run("garbage collected", func(t *testing.T) {
+		output := &syncWriter{mockWriter: newMockWriter()}
+
+		New(module, WithRoute(output, DEBUG, FATAL)).With(zap.String("key", "value"))
+
+		require.Eventually(t, func() bool {
+			runtime.GC()
+
+			output.syncs.Store(0)
+
+			require.NoError(t, Sync())
+
+			return output.syncs.Load() == 0
+		}, time.Second, 10*time.Millisecond)
+	})
+}
+
+func TestGetModules(t *testing.T) {
+	New("registry-module1")
+	SetLevel("registry-module2", DEBUG)
+	SetLevelFor("registry-module3", DEBUG, time.Minute)
+
+	defer SetLevelFor("registry-module3", DEBUG, 0)
+
+	modules := GetModules()
+
+	require.Contains(t, modules, "registry-module1")
+	require.Contains(t, modules, "registry-module2")
+	require.Contains(t, modules, "registry-module3")
+	require.NotContains(t, modules, defaultModuleName)
+	require.IsNonDecreasing(t, modules)
+}
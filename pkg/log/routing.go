@@ -0,0 +1,148 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package log
+
+import (
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// route routes the log entries within a range of levels (and optionally for specific modules) to an output.
+type route struct {
+	output   zapcore.WriteSyncer
+	core     zapcore.Core
+	encoding Encoding
+	minLevel Level
+	maxLevel Level
+	modules  []string
+}
+
+// RouteOption is an option for WithRoute.
+type RouteOption func(r *route)
+
+// WithRouteEncoding sets the encoding of the route. By default, the encoding of the logger is used.
+func WithRouteEncoding(encoding Encoding) RouteOption {
+	return func(r *route) {
+		r.encoding = encoding
+	}
+}
+
+// WithRouteModules restricts the route to the given modules (including their child modules,
+// for example "module1.sub"). By default, the route applies to all modules.
+func WithRouteModules(modules ...string) RouteOption {
+	return func(r *route) {
+		r.modules = modules
+	}
+}
+
+// WithRoute routes the log entries with a level between minLevel and maxLevel (inclusive) to the given output.
+// The option may be specified multiple times in order to route entries to any number of outputs. An entry is
+// written to every route that it matches and is discarded if it doesn't match any route.
+//
+// Specifying a route replaces the default routing, in which ERROR (and above) logs are written
+// to stderr (see WithStdErr) and all other logs are written to stdout (see WithStdOut).
+//
+// Example (all logs to stdout and, in addition, WARN and above to a file in JSON format):
+//
+//	log.New("module1",
+//		log.WithRoute(os.Stdout, log.DEBUG, log.FATAL),
+//		log.WithRoute(file, log.WARNING, log.FATAL, log.WithRouteEncoding(log.JSON)),
+//	)
+func WithRoute(output zapcore.WriteSyncer, minLevel, maxLevel Level, opts ...RouteOption) Option {
+	return func(o *options) {
+		if !o.routesSet {
+			// Replace the default (or configured) routes.
+			o.routes = nil
+			o.routesSet = true
+		}
+
+		r := &route{
+			output:   output,
+			minLevel: minLevel,
+			maxLevel: maxLevel,
+		}
+
+		for _, opt := range opts {
+			opt(r)
+		}
+
+		o.routes = append(o.routes, r)
+	}
+}
+
+// WithCore routes the log entries with a level between minLevel and maxLevel (inclusive) to the given zap core,
+// for example an observing core in tests. The core receives the entries that are enabled for the module (see
+// SetLevel) and doesn't use the encoding of the logger. As with WithRoute, the default routing is replaced.
+func WithCore(core zapcore.Core, minLevel, maxLevel Level, opts ...RouteOption) Option {
+	return func(o *options) {
+		WithRoute(nil, minLevel, maxLevel, opts...)(o)
+
+		o.routes[len(o.routes)-1].core = core
+	}
+}
+
+func defaultRoutes(stdOut, stdErr zapcore.WriteSyncer) []*route {
+	return []*route{
+		{output: stdErr, minLevel: ERROR, maxLevel: maxLogLevel},
+		{output: stdOut, minLevel: minLogLevel, maxLevel: WARNING},
+	}
+}
+
+// matches returns true if the route applies to the given module.
+func (r *route) matches(module string) bool {
+	if len(r.modules) == 0 {
+		return true
+	}
+
+	for _, m := range r.modules {
+		if module == m || strings.HasPrefix(module, m+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// levelEnabler returns the level enabler of the route for the given module. If ignoreModuleLevels is true
+// then the level of the module (see SetLevel) is not checked, in which case the caller decides which
+// entries are logged (see the context level overrides).
+func (r *route) levelEnabler(module string, levels *LevelRegistry, ignoreModuleLevels bool) zapcore.LevelEnabler {
+	minLevel, maxLevel := r.minLevel, r.maxLevel
+
+	return zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
+		return Level(lvl) >= minLevel && Level(lvl) <= maxLevel &&
+			(ignoreModuleLevels || levels.isEnabled(module, Level(lvl)))
+	})
+}
+
+// filterCore writes the entries that are enabled by both the level enabler and the wrapped core.
+type filterCore struct {
+	zapcore.Core
+	enabler zapcore.LevelEnabler
+}
+
+func newFilterCore(core zapcore.Core, enabler zapcore.LevelEnabler) zapcore.Core {
+	return &filterCore{Core: core, enabler: enabler}
+}
+
+func (c *filterCore) Enabled(level zapcore.Level) bool {
+	return c.enabler.Enabled(level) && c.Core.Enabled(level)
+}
+
+func (c *filterCore) With(fields []zapcore.Field) zapcore.Core {
+	return &filterCore{Core: c.Core.With(fields), enabler: c.enabler}
+}
+
+func (c *filterCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.enabler.Enabled(entry.Level) {
+		return ce
+	}
+
+	return c.Core.Check(entry, ce)
+}
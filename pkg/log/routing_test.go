@@ -0,0 +1,129 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package log
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestRoutes(t *testing.T) {
+	const module = "routing-module"
+
+	SetLevel(module, DEBUG)
+
+	t.Run("everything to one output", func(t *testing.T) {
+		output := newMockWriter()
+
+		logger := New(module, WithRoute(output, DEBUG, FATAL))
+
+		logger.Debug("Sample debug log")
+		logger.Info("Sample info log")
+		logger.Error("Sample error log")
+
+		require.Contains(t, output.String(), "Sample debug log")
+		require.Contains(t, output.String(), "Sample info log")
+		require.Contains(t, output.String(), "Sample error log")
+	})
+
+	t.Run("overlapping routes with own encoding", func(t *testing.T) {
+		all := newMockWriter()
+		warnings := newMockWriter()
+
+		logger := New(module,
+			WithEncoding(JSON),
+			WithRoute(all, DEBUG, FATAL),
+			WithRoute(warnings, WARNING, ERROR, WithRouteEncoding(Console)),
+		)
+
+		logger.Info("Sample info log")
+		logger.Warn("Sample warn log")
+		logger.Error("Sample error log")
+
+		require.Contains(t, all.String(), `"msg":"Sample info log"`)
+		require.Contains(t, all.String(), `"msg":"Sample warn log"`)
+		require.Contains(t, all.String(), `"msg":"Sample error log"`)
+
+		require.NotContains(t, warnings.String(), "Sample info log")
+		require.Contains(t, warnings.String(), "WARN\t["+module+"]")
+		require.Contains(t, warnings.String(), "ERROR\t["+module+"]")
+	})
+
+	t.Run("modules", func(t *testing.T) {
+		general := newMockWriter()
+		audit := newMockWriter()
+
+		opts := []Option{
+			WithRoute(general, DEBUG, FATAL, WithRouteModules(module)),
+			WithRoute(audit, INFO, FATAL, WithRouteModules("audit")),
+		}
+
+		New(module, opts...).Info("Sample info log")
+		New("audit", opts...).Info("Sample audit log")
+		New("audit.sub", opts...).Info("Sample audit sub log")
+		New("auditor", opts...).Info("Sample auditor log")
+
+		require.Contains(t, general.String(), "Sample info log")
+		require.NotContains(t, general.String(), "audit")
+
+		require.Contains(t, audit.String(), "Sample audit log")
+		require.Contains(t, audit.String(), "Sample audit sub log")
+		require.NotContains(t, audit.String(), "Sample info log")
+		require.NotContains(t, audit.String(), "Sample auditor log")
+	})
+
+	t.Run("std out/err replace routes", func(t *testing.T) {
+		routed := newMockWriter()
+		stdOut := newMockWriter()
+		stdErr := newMockWriter()
+
+		logger := New(module, WithRoute(routed, DEBUG, FATAL), WithStdOut(stdOut), WithStdErr(stdErr))
+
+		logger.Info("Sample info log")
+		logger.Error("Sample error log")
+
+		require.Empty(t, routed.String())
+		require.Contains(t, stdOut.String(), "Sample info log")
+		require.Contains(t, stdErr.String(), "Sample error log")
+	})
+
+	t.Run("module level still applies", func(t *testing.T) {
+		SetLevel(module+"-warn", WARNING)
+
+		output := newMockWriter()
+
+		logger := New(module+"-warn", WithRoute(output, DEBUG, FATAL))
+
+		logger.Info("Sample info log")
+		logger.Warn("Sample warn log")
+
+		require.NotContains(t, output.String(), "Sample info log")
+		require.Contains(t, output.String(), "Sample warn log")
+	})
+
+	t.Run("core", func(t *testing.T) {
+		core, logs := observer.New(zapcore.InfoLevel)
+		output := newMockWriter()
+
+		logger := New(module, WithRoute(output, DEBUG, FATAL), WithCore(core, DEBUG, WARNING))
+
+		logger.Debug("Sample debug log")
+		logger.Warn("Sample warn log", zap.String("key", "value"))
+		logger.Error("Sample error log")
+
+		require.Equal(t, 1, logs.Len())
+		require.Equal(t, "Sample warn log", logs.All()[0].Message)
+		require.Equal(t, "value", logs.All()[0].ContextMap()["key"])
+
+		require.Contains(t, output.String(), "Sample debug log")
+		require.Contains(t, output.String(), "Sample error log")
+	})
+}
@@ -0,0 +1,175 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package log
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap/zapcore"
+)
+
+const spanEventMinLevel = zapcore.WarnLevel
+
+// spanEventCore wraps the core of the context logger and, for entries at WARN level and above,
+// adds a spanEventWriter to the checked entry so that the entry is also recorded on the span
+// found in the context.
+type spanEventCore struct {
+	zapcore.Core
+	writer *spanEventWriter
+}
+
+func newSpanEventCore(core zapcore.Core) zapcore.Core {
+	return &spanEventCore{Core: core, writer: &spanEventWriter{}}
+}
+
+func (c *spanEventCore) With(fields []zapcore.Field) zapcore.Core {
+	return &spanEventCore{Core: c.Core.With(fields), writer: c.writer.with(fields)}
+}
+
+func (c *spanEventCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if entry.Level >= spanEventMinLevel && c.Core.Enabled(entry.Level) {
+		ce = ce.AddCore(entry, c.writer)
+	}
+
+	return c.Core.Check(entry, ce)
+}
+
+// spanEventWriter records a log entry as an event on the span which is found in the context
+// of the tracing field (see WithTracing). The fields of the logger (see With) are recorded along
+// with the fields of the entry. If the entry is at ERROR level or above then the span status is
+// set to Error. Error fields are recorded using span.RecordError.
+type spanEventWriter struct {
+	fields []zapcore.Field
+}
+
+func (w *spanEventWriter) with(fields []zapcore.Field) *spanEventWriter {
+	return &spanEventWriter{fields: append(w.fields[:len(w.fields):len(w.fields)], fields...)}
+}
+
+func (w *spanEventWriter) Enabled(zapcore.Level) bool {
+	return true
+}
+
+func (w *spanEventWriter) With(fields []zapcore.Field) zapcore.Core {
+	return w.with(fields)
+}
+
+func (w *spanEventWriter) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return ce.AddCore(entry, w)
+}
+
+func (w *spanEventWriter) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	ctx := contextFromFields(fields)
+	if ctx == nil {
+		return nil
+	}
+
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return nil
+	}
+
+	enc := zapcore.NewMapObjectEncoder()
+
+	for _, fieldSet := range [][]zapcore.Field{w.fields, fields} {
+		for _, field := range fieldSet {
+			if isTracingField(field) {
+				continue
+			}
+
+			if err, ok := errorFromField(field); ok {
+				span.RecordError(err)
+			}
+
+			field.AddTo(enc)
+		}
+	}
+
+	attrs := append(make([]attribute.KeyValue, 0, len(enc.Fields)+1),
+		attribute.String("log.severity", Level(entry.Level).String()))
+
+	if entry.LoggerName != "" {
+		attrs = append(attrs, attribute.String("log.logger", entry.LoggerName))
+	}
+
+	span.AddEvent(entry.Message, trace.WithAttributes(append(attrs, toAttributes(enc.Fields)...)...))
+
+	if entry.Level >= zapcore.ErrorLevel {
+		span.SetStatus(codes.Error, entry.Message)
+	}
+
+	return nil
+}
+
+func (w *spanEventWriter) Sync() error {
+	return nil
+}
+
+func isTracingField(field zapcore.Field) bool {
+	_, ok := field.Interface.(*otelMarshaller)
+
+	return ok
+}
+
+func contextFromFields(fields []zapcore.Field) context.Context {
+	for _, field := range fields {
+		if m, ok := field.Interface.(*otelMarshaller); ok {
+			return m.ctx
+		}
+	}
+
+	return nil
+}
+
+func toAttributes(fields map[string]interface{}) []attribute.KeyValue {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	attrs := make([]attribute.KeyValue, 0, len(keys))
+
+	for _, key := range keys {
+		attrs = append(attrs, toAttribute(key, fields[key]))
+	}
+
+	return attrs
+}
+
+func toAttribute(key string, value interface{}) attribute.KeyValue {
+	switch v := value.(type) {
+	case string:
+		return attribute.String(key, v)
+	case bool:
+		return attribute.Bool(key, v)
+	case int:
+		return attribute.Int(key, v)
+	case int64:
+		return attribute.Int64(key, v)
+	case int32:
+		return attribute.Int64(key, int64(v))
+	case uint32:
+		return attribute.Int64(key, int64(v))
+	case float64:
+		return attribute.Float64(key, v)
+	case float32:
+		return attribute.Float64(key, float64(v))
+	case error:
+		return attribute.String(key, v.Error())
+	case fmt.Stringer:
+		return attribute.String(key, v.String())
+	default:
+		return attribute.String(key, fmt.Sprint(v))
+	}
+}
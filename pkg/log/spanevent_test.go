@@ -0,0 +1,141 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package log
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestSpanEvents(t *testing.T) {
+	const module = "span-events-module"
+
+	SetLevel(module, DEBUG)
+
+	t.Run("enabled", func(t *testing.T) {
+		recorder := tracetest.NewSpanRecorder()
+		tracer := trace.NewTracerProvider(trace.WithSpanProcessor(recorder)).Tracer("unit-test")
+
+		stdOut := newMockWriter()
+		stdErr := newMockWriter()
+
+		logger := New(module, WithStdOut(stdOut), WithStdErr(stdErr), WithSpanEvents())
+
+		ctx, span := tracer.Start(context.Background(), "span1")
+
+		logger.Debugc(ctx, "Sample debug log")
+		logger.Infoc(ctx, "Sample info log")
+		logger.Warnc(ctx, "Sample warn log", WithID("id1"), WithHTTPStatus(404))
+		logger.Errorc(ctx, "Sample error log", WithError(errors.New("some error")))
+
+		span.End()
+
+		require.Contains(t, stdOut.Buffer.String(), "Sample warn log")
+		require.Contains(t, stdErr.Buffer.String(), "Sample error log")
+
+		spans := recorder.Ended()
+		require.Len(t, spans, 1)
+
+		events := spans[0].Events()
+		require.Len(t, events, 3)
+
+		require.Equal(t, "Sample warn log", events[0].Name)
+		require.Contains(t, events[0].Attributes, attribute.String("log.severity", "WARN"))
+		require.Contains(t, events[0].Attributes, attribute.String("log.logger", module))
+		require.Contains(t, events[0].Attributes, attribute.String(FieldID, "id1"))
+		require.Contains(t, events[0].Attributes, attribute.Int64(FieldHTTPStatus, 404))
+
+		require.Equal(t, "exception", events[1].Name)
+
+		require.Equal(t, "Sample error log", events[2].Name)
+		require.Contains(t, events[2].Attributes, attribute.String("log.severity", "ERROR"))
+		require.Contains(t, events[2].Attributes, attribute.String("error", "some error"))
+
+		require.Equal(t, codes.Error, spans[0].Status().Code)
+		require.Equal(t, "Sample error log", spans[0].Status().Description)
+	})
+
+	t.Run("logger fields", func(t *testing.T) {
+		recorder := tracetest.NewSpanRecorder()
+		tracer := trace.NewTracerProvider(trace.WithSpanProcessor(recorder)).Tracer("unit-test")
+
+		logger := New(module, WithStdOut(newMockWriter()), WithSpanEvents(), WithFields(WithName("issuer"))).
+			With(WithTxID("tx1")).
+			With(WithError(errors.New("cached error")))
+
+		ctx, span := tracer.Start(context.Background(), "span1")
+
+		logger.Warnc(ctx, "Sample warn log", WithID("id1"))
+
+		span.End()
+
+		events := recorder.Ended()[0].Events()
+		require.Len(t, events, 2)
+
+		require.Equal(t, "exception", events[0].Name)
+
+		require.Equal(t, "Sample warn log", events[1].Name)
+		require.Contains(t, events[1].Attributes, attribute.String(FieldName, "issuer"))
+		require.Contains(t, events[1].Attributes, attribute.String(FieldTxID, "tx1"))
+		require.Contains(t, events[1].Attributes, attribute.String("error", "cached error"))
+		require.Contains(t, events[1].Attributes, attribute.String(FieldID, "id1"))
+	})
+
+	t.Run("level disabled", func(t *testing.T) {
+		SetLevel(module+"-error", ERROR)
+
+		recorder := tracetest.NewSpanRecorder()
+		tracer := trace.NewTracerProvider(trace.WithSpanProcessor(recorder)).Tracer("unit-test")
+
+		logger := New(module+"-error", WithStdOut(newMockWriter()), WithStdErr(newMockWriter()), WithSpanEvents())
+
+		ctx, span := tracer.Start(context.Background(), "span1")
+
+		logger.Warnc(ctx, "Sample warn log")
+
+		span.End()
+
+		spans := recorder.Ended()
+		require.Len(t, spans, 1)
+		require.Empty(t, spans[0].Events())
+		require.Equal(t, codes.Unset, spans[0].Status().Code)
+	})
+
+	t.Run("not enabled", func(t *testing.T) {
+		recorder := tracetest.NewSpanRecorder()
+		tracer := trace.NewTracerProvider(trace.WithSpanProcessor(recorder)).Tracer("unit-test")
+
+		logger := New(module, WithStdOut(newMockWriter()), WithStdErr(newMockWriter()))
+
+		ctx, span := tracer.Start(context.Background(), "span1")
+
+		logger.Errorc(ctx, "Sample error log")
+
+		span.End()
+
+		spans := recorder.Ended()
+		require.Len(t, spans, 1)
+		require.Empty(t, spans[0].Events())
+	})
+
+	t.Run("no span in context", func(t *testing.T) {
+		stdErr := newMockWriter()
+
+		logger := New(module, WithStdOut(newMockWriter()), WithStdErr(stdErr), WithSpanEvents())
+
+		logger.Errorc(context.Background(), "Sample error log")
+
+		require.Contains(t, stdErr.Buffer.String(), "Sample error log")
+	})
+}
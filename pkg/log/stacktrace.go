@@ -0,0 +1,258 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package log
+
+import (
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// initialStackDepth is the initial size of the buffer of program counters, which is grown until the
+// whole stack fits.
+const initialStackDepth = 64
+
+// defaultStacktraceSkipPackages are the packages whose frames are omitted from stack traces.
+var defaultStacktraceSkipPackages = []string{ //nolint:gochecknoglobals
+	"go.uber.org/zap",
+	"github.com/trustbloc/logutil-go/pkg/log.",
+}
+
+type stacktraceOptions struct {
+	level        Level
+	depth        int
+	skipPackages []string
+	errorStack   bool
+}
+
+// StacktraceOption is an option for WithStacktrace.
+type StacktraceOption func(o *stacktraceOptions)
+
+// WithStacktraceDepth limits the number of frames in a stack trace. By default, the depth is not limited.
+func WithStacktraceDepth(depth int) StacktraceOption {
+	return func(o *stacktraceOptions) {
+		o.depth = depth
+	}
+}
+
+// WithStacktraceSkipPackages omits the frames of the given packages (and their sub-packages) from stack traces,
+// for example the packages of HTTP middleware. The frames of zap and of this package are always omitted.
+func WithStacktraceSkipPackages(packages ...string) StacktraceOption {
+	return func(o *stacktraceOptions) {
+		o.skipPackages = append(o.skipPackages, packages...)
+	}
+}
+
+// WithErrorStacktrace outputs the stack trace of an error field (see WithError and WithErrorDetails), if the
+// error (or an error that it wraps) holds a stack trace, instead of the stack trace of the logging call.
+// Supported are errors with either a 'StackTrace()' method which returns a slice of program counters
+// (for example, github.com/pkg/errors) or a 'Callers() []uintptr' method.
+func WithErrorStacktrace() StacktraceOption {
+	return func(o *stacktraceOptions) {
+		o.errorStack = true
+	}
+}
+
+// WithStacktrace captures stack traces for log entries at the given level and above.
+//
+// Example:
+//
+//	log.New("module1", log.WithStacktrace(log.ERROR, log.WithStacktraceDepth(10), log.WithErrorStacktrace()))
+func WithStacktrace(level Level, opts ...StacktraceOption) Option {
+	return func(o *options) {
+		st := &stacktraceOptions{
+			level:        level,
+			skipPackages: append([]string{}, defaultStacktraceSkipPackages...),
+		}
+
+		for _, opt := range opts {
+			opt(st)
+		}
+
+		o.stacktrace = st
+	}
+}
+
+// stacktraceCore sets the stack trace of the entries at the configured level (and above) before they
+// are written to the wrapped core, which is the tee of the routes. The entry is checked again against the
+// wrapped core once the stack trace is set, so the stack is captured once per entry rather than per route.
+type stacktraceCore struct {
+	zapcore.Core
+	options *stacktraceOptions
+}
+
+func newStacktraceCore(core zapcore.Core, options *stacktraceOptions) zapcore.Core {
+	return &stacktraceCore{Core: core, options: options}
+}
+
+func (c *stacktraceCore) With(fields []zapcore.Field) zapcore.Core {
+	return &stacktraceCore{Core: c.Core.With(fields), options: c.options}
+}
+
+func (c *stacktraceCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if Level(entry.Level) < c.options.level {
+		return c.Core.Check(entry, ce)
+	}
+
+	if c.Enabled(entry.Level) {
+		return ce.AddCore(entry, c)
+	}
+
+	return ce
+}
+
+func (c *stacktraceCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	entry.Stack = c.options.stack(fields)
+
+	if ce := c.Core.Check(entry, nil); ce != nil {
+		writeChecked(ce, entry, fields)
+	}
+
+	return nil
+}
+
+// stack returns the formatted stack trace of an error field (if enabled and available)
+// or, otherwise, of the caller.
+func (o *stacktraceOptions) stack(fields []zapcore.Field) string {
+	if o.errorStack {
+		for _, field := range fields {
+			if err, ok := errorFromField(field); ok {
+				if pcs := errorStack(err); len(pcs) > 0 {
+					return o.format(pcs)
+				}
+			}
+		}
+	}
+
+	return o.format(callers())
+}
+
+// callers returns the program counters of the whole stack of the caller.
+func callers() []uintptr {
+	pcs := make([]uintptr, initialStackDepth)
+
+	for {
+		n := runtime.Callers(2, pcs)
+		if n < len(pcs) {
+			return pcs[:n]
+		}
+
+		pcs = make([]uintptr, len(pcs)*2)
+	}
+}
+
+// format formats the stack trace in the same way as zap.
+func (o *stacktraceOptions) format(pcs []uintptr) string {
+	var sb strings.Builder
+
+	frames := runtime.CallersFrames(pcs)
+	n := 0
+
+	for {
+		frame, more := frames.Next()
+
+		if !o.skip(frame.Function) && !strings.HasPrefix(frame.Function, "runtime.") {
+			if o.depth > 0 && n == o.depth {
+				break
+			}
+
+			if n > 0 {
+				sb.WriteByte('\n')
+			}
+
+			sb.WriteString(frame.Function)
+			sb.WriteString("\n\t")
+			sb.WriteString(frame.File)
+			sb.WriteByte(':')
+			sb.WriteString(strconv.Itoa(frame.Line))
+
+			n++
+		}
+
+		if !more {
+			break
+		}
+	}
+
+	return sb.String()
+}
+
+// skip returns true if the given function belongs to one of the skipped packages. A package ending with
+// '.' only matches the package itself, otherwise sub-packages also match.
+func (o *stacktraceOptions) skip(function string) bool {
+	for _, pkg := range o.skipPackages {
+		if !strings.HasPrefix(function, pkg) {
+			continue
+		}
+
+		if strings.HasSuffix(pkg, ".") || len(function) == len(pkg) {
+			return true
+		}
+
+		if c := function[len(pkg)]; c == '.' || c == '/' {
+			return true
+		}
+	}
+
+	return false
+}
+
+// errorStack returns the stack trace of the innermost error in the chain of the given error which holds
+// a stack trace, since that is closest to the origin of the error.
+func errorStack(err error) []uintptr {
+	switch e := err.(type) { //nolint:errorlint
+	case interface{ Unwrap() error }:
+		if pcs := errorStack(e.Unwrap()); len(pcs) > 0 {
+			return pcs
+		}
+	case interface{ Unwrap() []error }:
+		for _, wrapped := range e.Unwrap() {
+			if pcs := errorStack(wrapped); len(pcs) > 0 {
+				return pcs
+			}
+		}
+	}
+
+	return stackOf(err)
+}
+
+func stackOf(err error) []uintptr {
+	if err == nil {
+		return nil
+	}
+
+	if c, ok := err.(interface{ Callers() []uintptr }); ok { //nolint:errorlint
+		return c.Callers()
+	}
+
+	v := reflect.ValueOf(err)
+	if v.Kind() == reflect.Ptr && v.IsNil() {
+		return nil
+	}
+
+	m := v.MethodByName("StackTrace")
+	if !m.IsValid() || m.Type().NumIn() != 0 || m.Type().NumOut() != 1 {
+		return nil
+	}
+
+	if t := m.Type().Out(0); t.Kind() != reflect.Slice || t.Elem().Kind() != reflect.Uintptr {
+		return nil
+	}
+
+	frames := m.Call(nil)[0]
+
+	pcs := make([]uintptr, frames.Len())
+
+	for i := range pcs {
+		pcs[i] = uintptr(frames.Index(i).Uint())
+	}
+
+	return pcs
+}
@@ -0,0 +1,200 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package log
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type frame uintptr
+
+// stackError mimics the errors of github.com/pkg/errors.
+type stackError struct {
+	msg   string
+	stack []frame
+}
+
+func newStackError(msg string) error {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(2, pcs)
+
+	stack := make([]frame, n)
+	for i := range stack {
+		stack[i] = frame(pcs[i])
+	}
+
+	return &stackError{msg: msg, stack: stack}
+}
+
+func (e *stackError) Error() string {
+	return e.msg
+}
+
+func (e *stackError) StackTrace() []frame {
+	return e.stack
+}
+
+type callersError struct {
+	callers []uintptr
+}
+
+func (e *callersError) Error() string {
+	return "callers error"
+}
+
+func (e *callersError) Callers() []uintptr {
+	return e.callers
+}
+
+// countingCallersError counts how often its stack trace is captured.
+type countingCallersError struct {
+	calls int
+}
+
+func (e *countingCallersError) Error() string {
+	return "counting callers error"
+}
+
+func (e *countingCallersError) Callers() []uintptr {
+	e.calls++
+
+	pcs := make([]uintptr, 32)
+
+	return pcs[:runtime.Callers(1, pcs)]
+}
+
+func originOfError() error {
+	return newStackError("origin")
+}
+
+func TestStacktrace(t *testing.T) {
+	const module = "stacktrace-module"
+
+	SetLevel(module, DEBUG)
+
+	t.Run("caller", func(t *testing.T) {
+		entries := logEntries(t, module, JSON, func(l *Log) {
+			l.Warnc(context.Background(), "Sample warn log")
+			l.Errorc(context.Background(), "Sample error log")
+			l.Error("Sample error log")
+		}, WithStacktrace(ERROR))
+
+		require.NotContains(t, entries[0], "stacktrace")
+
+		for _, entry := range entries[1:] {
+			stack, ok := entry["stacktrace"].(string)
+			require.True(t, ok)
+
+			require.True(t, strings.HasPrefix(stack, "testing.tRunner\n"), stack)
+			require.NotContains(t, stack, "go.uber.org/zap")
+			require.NotContains(t, stack, "logutil-go/pkg/log.")
+		}
+	})
+
+	t.Run("depth and skip packages", func(t *testing.T) {
+		o := &stacktraceOptions{skipPackages: []string{"github.com/trustbloc/logutil-go/pkg/log.", "testing"}}
+
+		require.Empty(t, o.stack(nil))
+
+		o = &stacktraceOptions{depth: 1}
+
+		stack := o.stack(nil)
+		require.Len(t, strings.Split(stack, "\n"), 2)
+		require.True(t, strings.HasPrefix(stack, "github.com/trustbloc/logutil-go/pkg/log.(*stacktraceOptions).stack"))
+	})
+
+	t.Run("error stack", func(t *testing.T) {
+		err := fmt.Errorf("wrapped: %w", errors.Join(errors.New("other"), originOfError()))
+
+		// This package is not skipped so that the origin of the error (in this test) is included.
+		o := &stacktraceOptions{errorStack: true}
+
+		stack := o.stack([]zapcore.Field{zap.String("key", "value"), WithError(err)})
+		require.True(t, strings.HasPrefix(stack, "github.com/trustbloc/logutil-go/pkg/log.originOfError\n"), stack)
+
+		// The stack of the caller if the error has no stack.
+		stack = o.stack([]zapcore.Field{WithError(errors.New("no stack"))})
+		require.True(t, strings.HasPrefix(stack, "github.com/trustbloc/logutil-go/pkg/log.(*stacktraceOptions).stack\n"), stack)
+
+		entries := logEntries(t, module, JSON, func(l *Log) {
+			l.Error("Sample error log", WithError(err))
+		}, WithStacktrace(ERROR, WithErrorStacktrace(), WithStacktraceSkipPackages("testing")))
+
+		// All frames of the error stack are skipped.
+		require.Empty(t, entries[0]["stacktrace"])
+	})
+
+	t.Run("deep stack", func(t *testing.T) {
+		var recurse func(n int) string
+
+		recurse = func(n int) string {
+			if n == 0 {
+				return (&stacktraceOptions{}).stack(nil)
+			}
+
+			return recurse(n - 1)
+		}
+
+		// The stack isn't truncated.
+		require.Greater(t, strings.Count(recurse(200), "TestStacktrace.func"), 200)
+	})
+
+	t.Run("captured once for all routes", func(t *testing.T) {
+		err := &countingCallersError{}
+
+		stdOut := newMockWriter()
+		stdErr := newMockWriter()
+
+		logger := New(module, WithRoute(stdOut, DEBUG, FATAL), WithRoute(stdErr, ERROR, FATAL),
+			WithStacktrace(ERROR, WithErrorStacktrace()))
+
+		logger.Warn("Sample warn log", WithError(err))
+		require.Zero(t, err.calls)
+
+		logger.Error("Sample error log", WithError(err))
+		require.Equal(t, 1, err.calls)
+
+		require.Contains(t, stdOut.String(), `"stacktrace":"`)
+		require.Contains(t, stdErr.String(), `"stacktrace":"`)
+	})
+
+	t.Run("write error", func(t *testing.T) {
+		errorOutput := captureNestedErrorOutput(t)
+
+		New(module, WithStdErr(&failingWriter{}), WithStacktrace(ERROR)).Error("Sample error log")
+
+		require.Contains(t, errorOutput.String(), "write error: collector unavailable")
+	})
+
+	t.Run("callers error", func(t *testing.T) {
+		pcs := make([]uintptr, 32)
+		pcs = pcs[:runtime.Callers(1, pcs)]
+
+		require.Equal(t, pcs, errorStack(fmt.Errorf("wrapped: %w", &callersError{callers: pcs})))
+		require.Nil(t, errorStack(errors.New("no stack")))
+		require.Nil(t, stackOf((*stackError)(nil)))
+	})
+}
+
+func TestStacktraceSkip(t *testing.T) {
+	o := &stacktraceOptions{skipPackages: []string{"github.com/labstack/echo", "github.com/trustbloc/logutil-go/pkg/log."}}
+
+	require.True(t, o.skip("github.com/labstack/echo.(*Echo).ServeHTTP"))
+	require.True(t, o.skip("github.com/labstack/echo/middleware.Logger"))
+	require.False(t, o.skip("github.com/labstack/echo2.Handler"))
+	require.True(t, o.skip("github.com/trustbloc/logutil-go/pkg/log.(*Log).Errorc"))
+	require.False(t, o.skip("github.com/trustbloc/logutil-go/pkg/log/logtest.New"))
+}
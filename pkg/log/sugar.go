@@ -0,0 +1,198 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package log
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// badKey is the key of the values in the keys and values of the sugared functions which don't
+// have a (string) key.
+const badKey = "!BADKEY"
+
+// Tracecf formats a message according to the format specifier and logs it at Trace level,
+// including any implicit context fields (such as OpenTelemetry trace ID and span ID).
+func (l *Log) Tracecf(ctx context.Context, template string, args ...interface{}) {
+	if logger := l.sugaredLogger(ctx, TRACE); logger != nil {
+		logger.Log(zapcore.Level(TRACE), sprintf(template, args), WithTracing(ctx))
+	}
+}
+
+// Debugcf formats a message according to the format specifier and logs it at Debug level,
+// including any implicit context fields (such as OpenTelemetry trace ID and span ID).
+func (l *Log) Debugcf(ctx context.Context, template string, args ...interface{}) {
+	if logger := l.sugaredLogger(ctx, DEBUG); logger != nil {
+		logger.Debug(sprintf(template, args), WithTracing(ctx))
+	}
+}
+
+// Infocf formats a message according to the format specifier and logs it at Info level,
+// including any implicit context fields (such as OpenTelemetry trace ID and span ID).
+func (l *Log) Infocf(ctx context.Context, template string, args ...interface{}) {
+	if logger := l.sugaredLogger(ctx, INFO); logger != nil {
+		logger.Info(sprintf(template, args), WithTracing(ctx))
+	}
+}
+
+// Warncf formats a message according to the format specifier and logs it at Warning level,
+// including any implicit context fields (such as OpenTelemetry trace ID and span ID).
+func (l *Log) Warncf(ctx context.Context, template string, args ...interface{}) {
+	if logger := l.sugaredLogger(ctx, WARNING); logger != nil {
+		logger.Warn(sprintf(template, args), WithTracing(ctx))
+	}
+}
+
+// Errorcf formats a message according to the format specifier and logs it at Error level,
+// including any implicit context fields (such as OpenTelemetry trace ID and span ID).
+func (l *Log) Errorcf(ctx context.Context, template string, args ...interface{}) {
+	if logger := l.sugaredLogger(ctx, ERROR); logger != nil {
+		logger.Error(sprintf(template, args), WithTracing(ctx))
+	}
+}
+
+// Criticalcf formats a message according to the format specifier and logs it at Critical level,
+// including any implicit context fields (such as OpenTelemetry trace ID and span ID).
+func (l *Log) Criticalcf(ctx context.Context, template string, args ...interface{}) {
+	if logger := l.sugaredLogger(ctx, CRITICAL); logger != nil {
+		logger.Log(zapcore.Level(CRITICAL), sprintf(template, args), WithTracing(ctx))
+	}
+}
+
+// Paniccf formats a message according to the format specifier and logs it at Panic level,
+// including any implicit context fields (such as OpenTelemetry trace ID and span ID).
+//
+// The logger then panics, even if logging at PanicLevel is disabled.
+func (l *Log) Paniccf(ctx context.Context, template string, args ...interface{}) {
+	l.sugaredLogger(ctx, PANIC).Panic(sprintf(template, args), WithTracing(ctx))
+}
+
+// Fatalcf formats a message according to the format specifier and logs it at Fatal level,
+// including any implicit context fields (such as OpenTelemetry trace ID and span ID).
+//
+// The logger then calls os.Exit(1), even if logging at FatalLevel is disabled.
+func (l *Log) Fatalcf(ctx context.Context, template string, args ...interface{}) {
+	l.sugaredLogger(ctx, FATAL).Fatal(sprintf(template, args), WithTracing(ctx))
+}
+
+// Tracew logs a message at Trace level with the given loosely typed key-value pairs, including any
+// implicit context fields (such as OpenTelemetry trace ID and span ID). See Debugw for the key-value pairs.
+func (l *Log) Tracew(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	if logger := l.sugaredLogger(ctx, TRACE); logger != nil {
+		logger.Log(zapcore.Level(TRACE), msg, sweetenFields(ctx, keysAndValues)...)
+	}
+}
+
+// Debugw logs a message at Debug level with the given loosely typed key-value pairs, including any
+// implicit context fields (such as OpenTelemetry trace ID and span ID). The keys must be strings,
+// although zap fields may also be given in place of a key-value pair. For example:
+//
+//	logger.Debugw(ctx, "Credential verified", "id", id, "duration", d, log.WithError(err))
+//
+// A value without a (string) key is logged with the key "!BADKEY".
+func (l *Log) Debugw(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	if logger := l.sugaredLogger(ctx, DEBUG); logger != nil {
+		logger.Debug(msg, sweetenFields(ctx, keysAndValues)...)
+	}
+}
+
+// Infow logs a message at Info level with the given loosely typed key-value pairs, including any
+// implicit context fields (such as OpenTelemetry trace ID and span ID). See Debugw for the key-value pairs.
+func (l *Log) Infow(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	if logger := l.sugaredLogger(ctx, INFO); logger != nil {
+		logger.Info(msg, sweetenFields(ctx, keysAndValues)...)
+	}
+}
+
+// Warnw logs a message at Warning level with the given loosely typed key-value pairs, including any
+// implicit context fields (such as OpenTelemetry trace ID and span ID). See Debugw for the key-value pairs.
+func (l *Log) Warnw(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	if logger := l.sugaredLogger(ctx, WARNING); logger != nil {
+		logger.Warn(msg, sweetenFields(ctx, keysAndValues)...)
+	}
+}
+
+// Errorw logs a message at Error level with the given loosely typed key-value pairs, including any
+// implicit context fields (such as OpenTelemetry trace ID and span ID). See Debugw for the key-value pairs.
+func (l *Log) Errorw(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	if logger := l.sugaredLogger(ctx, ERROR); logger != nil {
+		logger.Error(msg, sweetenFields(ctx, keysAndValues)...)
+	}
+}
+
+// Criticalw logs a message at Critical level with the given loosely typed key-value pairs, including any
+// implicit context fields (such as OpenTelemetry trace ID and span ID). See Debugw for the key-value pairs.
+func (l *Log) Criticalw(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	if logger := l.sugaredLogger(ctx, CRITICAL); logger != nil {
+		logger.Log(zapcore.Level(CRITICAL), msg, sweetenFields(ctx, keysAndValues)...)
+	}
+}
+
+// Panicw logs a message at Panic level with the given loosely typed key-value pairs, including any
+// implicit context fields (such as OpenTelemetry trace ID and span ID). See Debugw for the key-value pairs.
+//
+// The logger then panics, even if logging at PanicLevel is disabled.
+func (l *Log) Panicw(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.sugaredLogger(ctx, PANIC).Panic(msg, sweetenFields(ctx, keysAndValues)...)
+}
+
+// Fatalw logs a message at Fatal level with the given loosely typed key-value pairs, including any
+// implicit context fields (such as OpenTelemetry trace ID and span ID). See Debugw for the key-value pairs.
+//
+// The logger then calls os.Exit(1), even if logging at FatalLevel is disabled.
+func (l *Log) Fatalw(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.sugaredLogger(ctx, FATAL).Fatal(msg, sweetenFields(ctx, keysAndValues)...)
+}
+
+// sugaredLogger returns the context logger for the given level or nil if the level is disabled, so that
+// the message and fields are only created for entries that are logged. The logger is always returned
+// for PANIC and FATAL so that the logger panics (or exits) even if the level is disabled.
+func (l *Log) sugaredLogger(ctx context.Context, level Level) *zap.Logger {
+	logger := l.ctxLoggerFor(ctx, level)
+
+	if level < PANIC && !logger.Core().Enabled(zapcore.Level(level)) {
+		return nil
+	}
+
+	return logger
+}
+
+func sprintf(template string, args []interface{}) string {
+	if len(args) == 0 {
+		return template
+	}
+
+	return fmt.Sprintf(template, args...)
+}
+
+// sweetenFields converts the given loosely typed key-value pairs (and fields) to fields and appends
+// the tracing field.
+func sweetenFields(ctx context.Context, keysAndValues []interface{}) []zap.Field {
+	fields := make([]zap.Field, 0, len(keysAndValues)/2+1)
+
+	for i := 0; i < len(keysAndValues); i++ {
+		switch key := keysAndValues[i].(type) {
+		case zap.Field:
+			fields = append(fields, key)
+		case string:
+			if i == len(keysAndValues)-1 {
+				fields = append(fields, zap.String(badKey, key))
+			} else {
+				i++
+
+				fields = append(fields, zap.Any(key, keysAndValues[i]))
+			}
+		default:
+			fields = append(fields, zap.Any(badKey, key))
+		}
+	}
+
+	return append(fields, WithTracing(ctx))
+}
@@ -0,0 +1,135 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package log
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.uber.org/zap"
+)
+
+func TestSugar(t *testing.T) {
+	const module = "sugar-module"
+
+	ctx, span := trace.NewTracerProvider().Tracer("unit-test").Start(context.Background(), "span")
+	defer span.End()
+
+	traceID := span.SpanContext().TraceID().String()
+
+	t.Run("format", func(t *testing.T) {
+		entries := logEntries(t, module, JSON, func(l *Log) {
+			l.Debugcf(ctx, "Verified %d credentials", 3)
+			l.Infocf(ctx, "Sample info log")
+			l.Warncf(ctx, "Sample %s log", "warn")
+			l.Errorcf(ctx, "Sample %s log", "error")
+
+			require.Panics(t, func() {
+				l.Paniccf(ctx, "Sample %s log", "panic")
+			})
+		})
+
+		require.Len(t, entries, 5)
+		require.Equal(t, "Verified 3 credentials", entries[0]["msg"])
+		require.Equal(t, "Sample info log", entries[1]["msg"])
+		require.Equal(t, "Sample warn log", entries[2]["msg"])
+		require.Equal(t, "Sample error log", entries[3]["msg"])
+		require.Equal(t, "Sample panic log", entries[4]["msg"])
+
+		for _, entry := range entries {
+			require.Equal(t, traceID, entry[FieldTraceID])
+			require.Contains(t, entry["caller"], "log/sugar_test.go:")
+		}
+	})
+
+	t.Run("key-value pairs", func(t *testing.T) {
+		entries := logEntries(t, module, JSON, func(l *Log) {
+			l.Debugw(ctx, "Sample debug log", "count", 3, WithError(errors.New("failed")), "key", "value")
+			l.Infow(ctx, "Sample info log", 42, "dangling")
+			l.Warnw(ctx, "Sample warn log")
+			l.Errorw(ctx, "Sample error log", zap.String("key", "value"))
+
+			require.Panics(t, func() {
+				l.Panicw(ctx, "Sample panic log")
+			})
+		})
+
+		require.Len(t, entries, 5)
+
+		require.EqualValues(t, 3, entries[0]["count"])
+		require.Equal(t, "failed", entries[0][FieldError])
+		require.Equal(t, "value", entries[0]["key"])
+
+		require.Equal(t, "dangling", entries[1][badKey])
+		require.Equal(t, "value", entries[3]["key"])
+
+		for _, entry := range entries {
+			require.Equal(t, traceID, entry[FieldTraceID])
+			require.Contains(t, entry["caller"], "log/sugar_test.go:")
+		}
+	})
+
+	t.Run("trace and critical", func(t *testing.T) {
+		output := newMockWriter()
+
+		SetLevel(module, TRACE)
+		defer SetLevel(module, INFO)
+
+		logger := New(module, WithRoute(output, TRACE, FATAL))
+
+		logger.Tracecf(ctx, "Sample %s log", "trace")
+		logger.Tracew(ctx, "Sample trace dump", "payload", "abc")
+		logger.Criticalcf(ctx, "Sample %s log", "critical")
+		logger.Criticalw(ctx, "Sample critical alert", "key", "value")
+
+		lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+		require.Len(t, lines, 4)
+
+		require.Contains(t, lines[0], `"level":"trace"`)
+		require.Contains(t, lines[0], `"caller":"log/sugar_test.go:`)
+		require.Contains(t, lines[0], `"msg":"Sample trace log"`)
+		require.Contains(t, lines[1], `"msg":"Sample trace dump","payload":"abc"`)
+		require.Contains(t, lines[2], `"level":"critical"`)
+		require.Contains(t, lines[2], `"msg":"Sample critical log"`)
+		require.Contains(t, lines[3], `"msg":"Sample critical alert","key":"value"`)
+
+		for _, line := range lines {
+			require.Contains(t, line, traceID)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		output := newMockWriter()
+
+		SetLevel(module, ERROR)
+
+		logger := New(module, WithRoute(output, DEBUG, FATAL))
+
+		logger.Tracecf(ctx, "Sample %s log", "trace")
+		logger.Tracew(ctx, "Sample trace log", "key", "value")
+		logger.Infocf(ctx, "Sample %s log", "info")
+		logger.Warnw(ctx, "Sample warn log", "key", "value")
+		require.Empty(t, output.String())
+
+		SetLevel(module, FATAL)
+
+		logger.Criticalcf(ctx, "Sample %s log", "critical")
+		logger.Criticalw(ctx, "Sample critical log", "key", "value")
+		require.Empty(t, output.String())
+
+		SetLevel(module, ERROR)
+
+		require.Panics(t, func() {
+			logger.Paniccf(ctx, "Sample %s log", "panic")
+		})
+		require.Contains(t, output.String(), "Sample panic log")
+	})
+}
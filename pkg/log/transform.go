@@ -0,0 +1,64 @@
+/*
+Copyright Gen Digital Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package log
+
+import (
+	"go.uber.org/zap/zapcore"
+)
+
+// transformCore wraps a core and transforms the fields (including the fields added with With)
+// before they are written. The core is intended to wrap an individual output core and not a tee,
+// since the Write function of a tee doesn't check the level of its cores.
+type transformCore struct {
+	zapcore.Core
+	transform func(fields []zapcore.Field) []zapcore.Field
+}
+
+func newTransformCore(core zapcore.Core, transform func(fields []zapcore.Field) []zapcore.Field) zapcore.Core {
+	return &transformCore{Core: core, transform: transform}
+}
+
+func (c *transformCore) With(fields []zapcore.Field) zapcore.Core {
+	return &transformCore{Core: c.Core.With(c.transform(fields)), transform: c.transform}
+}
+
+func (c *transformCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return ce.AddCore(entry, c)
+	}
+
+	return ce
+}
+
+func (c *transformCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(entry, c.transform(fields))
+}
+
+// replaceFields returns the fields with each field replaced by the result of the given function
+// (if the function returns true). The given slice is never modified; a copy is made if any field is replaced.
+func replaceFields(fields []zapcore.Field, replace func(field zapcore.Field) (zapcore.Field, bool)) []zapcore.Field {
+	var replaced []zapcore.Field
+
+	for i, field := range fields {
+		f, ok := replace(field)
+		if !ok {
+			continue
+		}
+
+		if replaced == nil {
+			replaced = append(make([]zapcore.Field, 0, len(fields)), fields...)
+		}
+
+		replaced[i] = f
+	}
+
+	if replaced == nil {
+		return fields
+	}
+
+	return replaced
+}